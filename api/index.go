@@ -40,14 +40,32 @@ func handleRoutes(w http.ResponseWriter, r *http.Request) {
 		handlers.HandlerJadwalSearch(w, r)
 	case strings.HasPrefix(r.URL.Path, "/jadwal/"):
 		handlers.HandlerJadwal(w, r)
+	case strings.HasPrefix(r.URL.Path, "/dosen/") && strings.HasSuffix(r.URL.Path, "/jadwal"):
+		handlers.HandlerDosenJadwal(w, r)
+	case r.URL.Path == "/kalender/now":
+		handlers.HandlerKegiatanNow(w, r)
+	case r.URL.Path == "/kalender/next":
+		handlers.HandlerKegiatanNext(w, r)
 	case r.URL.Path == "/kalender":
 		handlers.HandlerKegiatan(w, r)
 	case strings.HasPrefix(r.URL.Path, "/kelasbaru/"):
 		handlers.HandlerKelasbaru(w, r)
 	case strings.HasPrefix(r.URL.Path, "/uts/"):
 		handlers.HandlerUTS(w, r)
+	case strings.HasPrefix(r.URL.Path, "/uas/"):
+		handlers.HandlerUAS(w, r)
+	case strings.HasPrefix(r.URL.Path, "/uu/"):
+		handlers.HandlerUU(w, r)
 	case strings.HasPrefix(r.URL.Path, "/mahasiswabaru/"):
 		handlers.HandlerMahasiswaBaru(w, r)
+	case r.URL.Path == "/ruang/kosong":
+		handlers.HandlerRuangKosong(w, r)
+	case strings.HasPrefix(r.URL.Path, "/ruang/") && strings.HasSuffix(r.URL.Path, "/jadwal"):
+		handlers.HandlerRuangJadwal(w, r)
+	case strings.HasPrefix(r.URL.Path, "/ruang/"):
+		handlers.HandlerRuang(w, r)
+	case r.URL.Path == "/waktu" || strings.HasPrefix(r.URL.Path, "/waktu/"):
+		handlers.HandlerWaktu(w, r)
 	default:
 		utils.WriteNotFoundError(w)
 	}
@@ -3,23 +3,43 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Port            string
-	BaseURL         string
-	RateLimitPerMin int
-	AllowedOrigins  []string
+	Port              string
+	BaseURL           string
+	RateLimitPerMin   int
+	AllowedOrigins    []string
+	FixtureDir        string
+	CassetteMode      string
+	CassetteDir       string
+	CSRFTokenTTL      time.Duration
+	TimeSlotTTL       time.Duration
+	KategoriRulesFile string
+	GedungFile        string
+	OccupancyQueries  []string
+	OccupancyTTL      time.Duration
 }
 
 var AppConfig Config
 
 func LoadConfig() {
 	AppConfig = Config{
-		Port:            getEnvOrDefault("PORT", ":8080"),
-		BaseURL:         getEnvOrDefault("BASE_URL", "https://baak.gunadarma.ac.id"),
-		RateLimitPerMin: getEnvIntOrDefault("RATE_LIMIT_PER_MIN", 60),
-		AllowedOrigins:  getEnvSliceOrDefault("ALLOWED_ORIGINS", []string{"*"}),
+		Port:              getEnvOrDefault("PORT", ":8080"),
+		BaseURL:           getEnvOrDefault("BASE_URL", "https://baak.gunadarma.ac.id"),
+		RateLimitPerMin:   getEnvIntOrDefault("RATE_LIMIT_PER_MIN", 60),
+		AllowedOrigins:    getEnvSliceOrDefault("ALLOWED_ORIGINS", []string{"*"}),
+		FixtureDir:        getEnvOrDefault("FIXTURE_DIR", ""),
+		CassetteMode:      getEnvOrDefault("CASSETTE_MODE", ""),
+		CassetteDir:       getEnvOrDefault("CASSETTE_DIR", "cassettes"),
+		CSRFTokenTTL:      getEnvDurationOrDefault("CSRF_TOKEN_TTL", 30*time.Minute),
+		TimeSlotTTL:       getEnvDurationOrDefault("TIME_SLOT_TTL", 6*time.Hour),
+		KategoriRulesFile: getEnvOrDefault("KATEGORI_RULES_FILE", ""),
+		GedungFile:        getEnvOrDefault("GEDUNG_FILE", ""),
+		OccupancyQueries:  getEnvSliceOrDefault("OCCUPANCY_QUERIES", nil),
+		OccupancyTTL:      getEnvDurationOrDefault("OCCUPANCY_TTL", 24*time.Hour),
 	}
 }
 
@@ -39,9 +59,24 @@ func getEnvIntOrDefault(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
 func getEnvSliceOrDefault(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
-		return []string{value}
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		return values
 	}
 	return defaultValue
 }
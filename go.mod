@@ -6,10 +6,8 @@ toolchain go1.24.1
 
 require (
 	github.com/PuerkitoBio/goquery v1.10.2
+	golang.org/x/net v0.35.0
 	golang.org/x/time v0.3.0
 )
 
-require (
-	github.com/andybalholm/cascadia v1.3.3 // indirect
-	golang.org/x/net v0.35.0 // indirect
-)
+require github.com/andybalholm/cascadia v1.3.3 // indirect
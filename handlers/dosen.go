@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/yafyx/baak-api/models"
+	"github.com/yafyx/baak-api/utils"
+)
+
+func HandlerDosenJadwal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	nama := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/dosen/"), "/jadwal")
+	nama = strings.TrimSpace(nama)
+	if nama == "" {
+		utils.WriteValidationError(w, "Missing dosen name in URL")
+		return
+	}
+
+	// Validate input
+	if len(nama) < 3 {
+		utils.WriteValidationError(w, "Dosen name must be at least 3 characters long")
+		return
+	}
+
+	jadwal, err := utils.SearchJadwal(r.Context(), nama)
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return
+	}
+
+	response := struct {
+		Dosen  string        `json:"dosen"`
+		Jadwal models.Jadwal `json:"jadwal"`
+	}{
+		Dosen:  nama,
+		Jadwal: utils.FilterJadwalDosen(jadwal, nama),
+	}
+
+	utils.WriteJSONResponse(w, response)
+}
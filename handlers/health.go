@@ -8,9 +8,11 @@ import (
 )
 
 type HealthResponse struct {
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
-	Version   string    `json:"version"`
+	Status    string                `json:"status"`
+	Timestamp time.Time             `json:"timestamp"`
+	Version   string                `json:"version"`
+	Upstream  utils.ChallengeStatus `json:"upstream"`
+	Parsers   []utils.ParserStatus  `json:"parsers"`
 }
 
 func HandlerHealth(w http.ResponseWriter, r *http.Request) {
@@ -19,10 +21,24 @@ func HandlerHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	upstream := utils.GetChallengeStatus()
+	parsers := utils.GetParserStatuses()
+	status := "healthy"
+	if upstream.Challenged {
+		status = "degraded"
+	}
+	for _, parser := range parsers {
+		if parser.Status == utils.ParserStatusDrift {
+			status = "degraded"
+		}
+	}
+
 	response := HealthResponse{
-		Status:    "healthy",
+		Status:    status,
 		Timestamp: time.Now(),
 		Version:   "1.0.0",
+		Upstream:  upstream,
+		Parsers:   parsers,
 	}
 
 	utils.WriteJSONResponse(w, response)
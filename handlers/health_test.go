@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yafyx/baak-api/utils"
+)
+
+func TestHealthReportsChallenge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cf-Mitigated", "challenge")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<html><head><title>Just a moment...</title></head></html>"))
+	}))
+	defer server.Close()
+
+	if _, err := utils.FetchDocumentWithRetry(context.Background(), server.URL, "", 5); err == nil {
+		t.Fatal("expected the challenge page to fail the fetch")
+	}
+
+	recorder := httptest.NewRecorder()
+	HandlerHealth(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var response struct {
+		Data struct {
+			Status   string `json:"status"`
+			Upstream struct {
+				Challenged bool    `json:"challenged"`
+				Since      *string `json:"since"`
+			} `json:"upstream"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid /health response: %v", err)
+	}
+	if !response.Data.Upstream.Challenged || response.Data.Upstream.Since == nil {
+		t.Errorf("expected challenged:true with since, got %s", recorder.Body.String())
+	}
+	if response.Data.Status != "degraded" {
+		t.Errorf("status = %q, want degraded", response.Data.Status)
+	}
+}
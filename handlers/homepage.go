@@ -9,10 +9,19 @@ import (
 func HandlerHomepage(w http.ResponseWriter, r *http.Request) {
 	endpoints := []string{
 		"/jadwal/{kelas}",
+		"/dosen/{nama}/jadwal",
 		"/kalender",
+		"/kalender/now",
+		"/kalender/next",
 		"/kelasbaru/{kelas/npm/nama}",
 		"/uts/{kelas/dosen}",
+		"/uas/{kelas/dosen}",
+		"/uu/{kelas/dosen}",
 		"/mahasiswabaru/{kelas/nama}",
+		"/waktu/{tabel}",
+		"/ruang/{kode}",
+		"/ruang/{kode}/jadwal",
+		"/ruang/kosong",
 	}
 	utils.WriteJSONResponse(w, endpoints)
 }
@@ -1,12 +1,9 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
-	"net/url"
 	"strings"
 
-	"github.com/yafyx/baak-api/config"
 	"github.com/yafyx/baak-api/models"
 	"github.com/yafyx/baak-api/utils"
 )
@@ -29,22 +26,7 @@ func HandlerJadwal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch CSRF token from the base jadwal page
-	jadwalBaseURL := fmt.Sprintf("%s/jadwal", config.AppConfig.BaseURL)
-	token, err := utils.GetCSRFToken(jadwalBaseURL)
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get CSRF token: %v", err))
-		return
-	}
-
-	// Construct the search URL with the token
-	searchURL := fmt.Sprintf("%s/jadwal/cariJadKul?_token=%s&teks=%s",
-		config.AppConfig.BaseURL,
-		url.QueryEscape(token),
-		url.QueryEscape(search),
-	)
-
-	jadwal, err := utils.GetJadwal(searchURL)
+	jadwal, err := utils.SearchJadwal(r.Context(), search)
 	if err != nil {
 		utils.WriteHTTPError(w, err)
 		return
@@ -79,22 +61,36 @@ func HandlerJadwalSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch CSRF token from the base jadwal page
-	jadwalBaseURL := fmt.Sprintf("%s/jadwal", config.AppConfig.BaseURL)
-	token, err := utils.GetCSRFToken(jadwalBaseURL)
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get CSRF token: %v", err))
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "flat":
+	case "kelas":
+		var kelas []models.JadwalKelas
+		err := utils.WithJadwalSearch(r.Context(), search, func(searchURL string) error {
+			var err error
+			kelas, err = utils.GetJadwalPerKelas(r.Context(), searchURL)
+			return err
+		})
+		if err != nil {
+			utils.WriteHTTPError(w, err)
+			return
+		}
+
+		response := struct {
+			Query string               `json:"query"`
+			Kelas []models.JadwalKelas `json:"kelas"`
+		}{
+			Query: search,
+			Kelas: kelas,
+		}
+
+		utils.WriteJSONResponse(w, response)
+		return
+	default:
+		utils.WriteValidationError(w, "Format must be 'flat' or 'kelas'")
 		return
 	}
 
-	// Construct the search URL with the token
-	searchURL := fmt.Sprintf("%s/jadwal/cariJadKul?_token=%s&teks=%s",
-		config.AppConfig.BaseURL,
-		url.QueryEscape(token),
-		url.QueryEscape(search),
-	)
-
-	jadwal, err := utils.GetJadwal(searchURL)
+	jadwal, err := utils.SearchJadwal(r.Context(), search)
 	if err != nil {
 		utils.WriteHTTPError(w, err)
 		return
@@ -2,16 +2,98 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/yafyx/baak-api/models"
 	"github.com/yafyx/baak-api/utils"
 )
 
+// defaultNextLimit is how many upcoming activities /kalender/next returns
+const defaultNextLimit = 5
+
 func HandlerKegiatan(w http.ResponseWriter, r *http.Request) {
-	kegiatanList, err := utils.GetKegiatan(utils.BaseURL)
+	kategori := strings.ToLower(r.URL.Query().Get("kategori"))
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "flat":
+	case "tree":
+		kegiatanTree, err := utils.GetKegiatanTree(r.Context(), utils.BaseURL)
+		if err != nil {
+			utils.WriteHTTPError(w, err)
+			return
+		}
+		if kategori != "" {
+			kegiatanTree = utils.FilterKegiatanTree(kegiatanTree, kategori)
+		}
+		utils.WriteJSONResponse(w, kegiatanTree)
+		return
+	default:
+		utils.WriteValidationError(w, "Format must be 'flat' or 'tree'")
+		return
+	}
+
+	kegiatanList, err := utils.GetKegiatan(r.Context(), utils.BaseURL)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.WriteHTTPError(w, err)
 		return
 	}
 
+	if kategori != "" {
+		kegiatanList = utils.FilterKegiatan(kegiatanList, kategori)
+	}
+
 	utils.WriteJSONResponse(w, kegiatanList)
 }
+
+// kegiatanForDate reads the ?tanggal= and ?kategori= parameters shared by
+// /kalender/now and /kalender/next
+func kegiatanForDate(w http.ResponseWriter, r *http.Request) ([]models.Kegiatan, time.Time, bool) {
+	date := utils.TodayJakarta()
+	if tanggal := r.URL.Query().Get("tanggal"); tanggal != "" {
+		parsed, err := time.Parse(utils.ISODate, tanggal)
+		if err != nil {
+			utils.WriteValidationError(w, "Tanggal must be in YYYY-MM-DD format")
+			return nil, time.Time{}, false
+		}
+		date = parsed
+	}
+
+	kegiatanList, err := utils.GetKegiatan(r.Context(), utils.BaseURL)
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return nil, time.Time{}, false
+	}
+
+	if kategori := strings.ToLower(r.URL.Query().Get("kategori")); kategori != "" {
+		kegiatanList = utils.FilterKegiatan(kegiatanList, kategori)
+	}
+	return kegiatanList, date, true
+}
+
+func HandlerKegiatanNow(w http.ResponseWriter, r *http.Request) {
+	kegiatanList, date, ok := kegiatanForDate(w, r)
+	if !ok {
+		return
+	}
+	utils.WriteJSONResponse(w, utils.KegiatanAktifPada(kegiatanList, date))
+}
+
+func HandlerKegiatanNext(w http.ResponseWriter, r *http.Request) {
+	limit := defaultNextLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			utils.WriteValidationError(w, "Limit must be a positive number")
+			return
+		}
+		limit = parsed
+	}
+
+	kegiatanList, date, ok := kegiatanForDate(w, r)
+	if !ok {
+		return
+	}
+	utils.WriteJSONResponse(w, utils.KegiatanBerikutnya(kegiatanList, date, limit))
+}
@@ -20,33 +20,36 @@ func HandlerKelasbaru(w http.ResponseWriter, r *http.Request) {
 
 	searchTypes := []string{"Kelas", "NPM", "Nama"}
 	var kelasBaru []models.KelasBaru
-	var err error
 
-	kelasBaruBaseURL := fmt.Sprintf("%s/cariKelasBaru", config.AppConfig.BaseURL)
-	token, err := utils.GetCSRFToken(kelasBaruBaseURL)
-	if err != nil {
-		token, err = utils.GetCSRFToken(config.AppConfig.BaseURL)
-		if err != nil {
-			utils.WriteErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get CSRF token for KelasBaru: %v", err))
-			return
-		}
+	// Prefer the token from the search page, falling back to the homepage
+	tokenPages := []string{
+		fmt.Sprintf("%s/cariKelasBaru", config.AppConfig.BaseURL),
+		config.AppConfig.BaseURL,
 	}
 
-	for _, searchType := range searchTypes {
-		searchURL := fmt.Sprintf("%s/cariKelasBaru?_token=%s&tipeKelasBaru=%s&teks=%s",
-			config.AppConfig.BaseURL,
-			url.QueryEscape(token),
-			url.QueryEscape(searchType),
-			url.QueryEscape(searchTerm),
-		)
-		kelasBaru, err = utils.GetKelasbaru(searchURL)
-		if err != nil {
-			utils.WriteHTTPError(w, err)
-			return
-		}
-		if len(kelasBaru) > 0 {
-			break
+	err := utils.WithCSRFToken(r.Context(), tokenPages, func(token string) error {
+		for _, searchType := range searchTypes {
+			searchURL := fmt.Sprintf("%s/cariKelasBaru?_token=%s&tipeKelasBaru=%s&teks=%s",
+				config.AppConfig.BaseURL,
+				url.QueryEscape(token),
+				url.QueryEscape(searchType),
+				url.QueryEscape(searchTerm),
+			)
+
+			var err error
+			kelasBaru, err = utils.GetKelasbaru(r.Context(), searchURL)
+			if err != nil {
+				return err
+			}
+			if len(kelasBaru) > 0 {
+				break
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return
 	}
 
 	if len(kelasBaru) == 0 {
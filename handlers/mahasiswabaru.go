@@ -20,34 +20,36 @@ func HandlerMahasiswaBaru(w http.ResponseWriter, r *http.Request) {
 
 	searchTypes := []string{"Kelas", "Nama"}
 	var mahasiswaBaru []models.MahasiswaBaru
-	var err error
 
-	mhsBaruBaseURL := fmt.Sprintf("%s/cariMhsBaru", config.AppConfig.BaseURL)
-	token, err := utils.GetCSRFToken(mhsBaruBaseURL)
-	if err != nil {
-		token, err = utils.GetCSRFToken(config.AppConfig.BaseURL)
-		if err != nil {
-			utils.WriteErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get CSRF token for MahasiswaBaru: %v", err))
-			return
-		}
+	// Prefer the token from the search page, falling back to the homepage
+	tokenPages := []string{
+		fmt.Sprintf("%s/cariMhsBaru", config.AppConfig.BaseURL),
+		config.AppConfig.BaseURL,
 	}
 
-	for _, searchType := range searchTypes {
-		searchURL := fmt.Sprintf("%s/cariMhsBaru?_token=%s&tipeMhsBaru=%s&teks=%s",
-			config.AppConfig.BaseURL,
-			url.QueryEscape(token),
-			url.QueryEscape(searchType),
-			url.QueryEscape(searchTerm),
-		)
-
-		mahasiswaBaru, err = utils.GetMahasiswaBaru(searchURL)
-		if err != nil {
-			utils.WriteHTTPError(w, err)
-			return
-		}
-		if len(mahasiswaBaru) > 0 {
-			break
+	err := utils.WithCSRFToken(r.Context(), tokenPages, func(token string) error {
+		for _, searchType := range searchTypes {
+			searchURL := fmt.Sprintf("%s/cariMhsBaru?_token=%s&tipeMhsBaru=%s&teks=%s",
+				config.AppConfig.BaseURL,
+				url.QueryEscape(token),
+				url.QueryEscape(searchType),
+				url.QueryEscape(searchTerm),
+			)
+
+			var err error
+			mahasiswaBaru, err = utils.GetMahasiswaBaru(r.Context(), searchURL)
+			if err != nil {
+				return err
+			}
+			if len(mahasiswaBaru) > 0 {
+				break
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return
 	}
 
 	if len(mahasiswaBaru) == 0 {
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yafyx/baak-api/models"
+	"github.com/yafyx/baak-api/utils"
+)
+
+func HandlerRuang(w http.ResponseWriter, r *http.Request) {
+	kode := strings.TrimPrefix(r.URL.Path, "/ruang/")
+	if kode == "" {
+		utils.WriteValidationError(w, "Missing ruang code in URL")
+		return
+	}
+
+	ruang, err := utils.ParseRuang(kode)
+	if err != nil {
+		utils.WriteValidationError(w, "Ruang code must look like D462 or J1413")
+		return
+	}
+
+	utils.WriteJSONResponse(w, ruang)
+}
+
+func HandlerRuangJadwal(w http.ResponseWriter, r *http.Request) {
+	kode := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/ruang/"), "/jadwal")
+	if kode == "" {
+		utils.WriteValidationError(w, "Missing ruang code in URL")
+		return
+	}
+
+	ruang, err := utils.ParseRuang(kode)
+	if err != nil {
+		// Rooms such as "AULA" can still be looked up by their plain code
+		ruang = models.Ruang{Kode: strings.ToUpper(kode)}
+	}
+
+	jadwal, err := utils.GetRuangJadwal(r.Context(), ruang.Kode)
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return
+	}
+
+	response := struct {
+		Ruang  models.Ruang  `json:"ruang"`
+		Jadwal models.Jadwal `json:"jadwal"`
+	}{
+		Ruang:  ruang,
+		Jadwal: jadwal,
+	}
+
+	utils.WriteJSONResponse(w, response)
+}
+
+func HandlerRuangKosong(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	hari := utils.NormalizeHari(query.Get("hari"))
+	if hari == "" {
+		utils.WriteValidationError(w, "Hari must be a day from Senin to Sabtu")
+		return
+	}
+
+	jam := query.Get("jam")
+	if jam == "" {
+		utils.WriteValidationError(w, "Missing jam query parameter")
+		return
+	}
+
+	// jam is either a period number, which GetRuangKosong checks against the
+	// time-slot table, or a clock time such as 09:45
+	var slot models.WaktuSlot
+	if periode, err := strconv.Atoi(jam); err == nil {
+		slot.Periode = periode
+	} else {
+		var ok bool
+		slot, ok, err = utils.PeriodeAt(r.Context(), jam)
+		if err != nil {
+			utils.WriteHTTPError(w, err)
+			return
+		}
+		if !ok {
+			utils.WriteValidationError(w, "Jam must be a period number or a time within lecture hours, such as 09:45")
+			return
+		}
+	}
+
+	gedung := strings.ToUpper(query.Get("gedung"))
+	ruang, err := utils.GetRuangKosong(r.Context(), hari, slot.Periode, gedung)
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return
+	}
+
+	response := struct {
+		Hari    string         `json:"hari"`
+		Periode int            `json:"periode"`
+		Gedung  string         `json:"gedung,omitempty"`
+		Ruang   []models.Ruang `json:"ruang"`
+	}{
+		Hari:    hari,
+		Periode: slot.Periode,
+		Gedung:  gedung,
+		Ruang:   ruang,
+	}
+
+	utils.WriteJSONResponse(w, response)
+}
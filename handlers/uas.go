@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/yafyx/baak-api/config"
+	"github.com/yafyx/baak-api/models"
+	"github.com/yafyx/baak-api/utils"
+)
+
+func HandlerUAS(w http.ResponseWriter, r *http.Request) {
+	search := strings.TrimPrefix(r.URL.Path, "/uas/")
+	if search == "" {
+		utils.WriteValidationError(w, "Missing search term in URL")
+		return
+	}
+
+	var uas []models.UAS
+	err := searchUjian(r.Context(), "cariUas", search, func(searchURL string) error {
+		var err error
+		uas, err = utils.GetUAS(r.Context(), searchURL)
+		return err
+	})
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, uas)
+}
+
+// searchUjian calls fetch with the URL of an exam search page that, like
+// cariJadKul, needs the session's CSRF token
+func searchUjian(ctx context.Context, page, search string, fetch func(searchURL string) error) error {
+	// The exam search forms live on the jadwal page alongside cariJadKul
+	jadwalBaseURL := fmt.Sprintf("%s/jadwal", config.AppConfig.BaseURL)
+
+	return utils.WithCSRFToken(ctx, []string{jadwalBaseURL}, func(token string) error {
+		searchURL := fmt.Sprintf("%s/jadwal/%s?_token=%s&teks=%s",
+			config.AppConfig.BaseURL,
+			page,
+			url.QueryEscape(token),
+			url.QueryEscape(search),
+		)
+		return fetch(searchURL)
+	})
+}
@@ -16,9 +16,9 @@ func HandlerUTS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	url := fmt.Sprintf("%s/jadwal/cariUts?&teks=%s", utils.BaseURL, search)
-	uts, err := utils.GetUTS(url)
+	uts, err := utils.GetUTS(r.Context(), url)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		utils.WriteHTTPError(w, err)
 		return
 	}
 
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/yafyx/baak-api/models"
+	"github.com/yafyx/baak-api/utils"
+)
+
+func HandlerUU(w http.ResponseWriter, r *http.Request) {
+	search := strings.TrimPrefix(r.URL.Path, "/uu/")
+	if search == "" {
+		utils.WriteValidationError(w, "Missing search term in URL")
+		return
+	}
+
+	var uu []models.UU
+	err := searchUjian(r.Context(), "cariUu", search, func(searchURL string) error {
+		var err error
+		uu, err = utils.GetUU(r.Context(), searchURL)
+		return err
+	})
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return
+	}
+
+	utils.WriteJSONResponse(w, uu)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yafyx/baak-api/models"
+	"github.com/yafyx/baak-api/utils"
+)
+
+func HandlerWaktu(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		utils.WriteErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// The table can be chosen with /waktu/{n} or /waktu?tabel=n
+	tabelParam := strings.Trim(strings.TrimPrefix(r.URL.Path, "/waktu"), "/")
+	if tabelParam == "" {
+		tabelParam = r.URL.Query().Get("tabel")
+	}
+
+	tabel := utils.KuliahWaktuTable
+	if tabelParam != "" {
+		n, err := strconv.Atoi(tabelParam)
+		if err != nil || n < 1 {
+			utils.WriteValidationError(w, "Tabel must be a positive number")
+			return
+		}
+		tabel = n
+	}
+
+	// Only the lecture and exam tables are served
+	if !utils.IsWaktuTable(tabel) {
+		utils.WriteNotFoundError(w)
+		return
+	}
+
+	waktu, err := utils.Waktu(r.Context(), tabel)
+	if err != nil {
+		utils.WriteHTTPError(w, err)
+		return
+	}
+	if len(waktu) == 0 {
+		utils.WriteNotFoundError(w)
+		return
+	}
+
+	response := struct {
+		Tabel int                `json:"tabel"`
+		Waktu []models.WaktuSlot `json:"waktu"`
+	}{
+		Tabel: tabel,
+		Waktu: waktu,
+	}
+
+	utils.WriteJSONResponse(w, response)
+}
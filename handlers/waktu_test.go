@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerWaktuRejectsUnknownTables(t *testing.T) {
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/waktu/99", http.StatusNotFound},
+		{"/waktu?tabel=1", http.StatusNotFound},
+		{"/waktu/abc", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		HandlerWaktu(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if recorder.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.path, recorder.Code, tt.status)
+		}
+	}
+}
@@ -7,10 +7,34 @@ import (
 
 	handler "github.com/yafyx/baak-api/api"
 	"github.com/yafyx/baak-api/config"
+	"github.com/yafyx/baak-api/utils"
 )
 
 func main() {
 	config.LoadConfig()
+	fetcher, err := utils.NewFetcher(config.AppConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	scraper := utils.NewScraper(fetcher)
+	scraper.SetTokenTTL(config.AppConfig.CSRFTokenTTL)
+	scraper.SetTimeSlotTTL(config.AppConfig.TimeSlotTTL)
+	if path := config.AppConfig.KategoriRulesFile; path != "" {
+		rules, err := utils.LoadKategoriRules(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scraper.SetKategoriRules(rules)
+	}
+	if path := config.AppConfig.GedungFile; path != "" {
+		registry, err := utils.LoadGedungRegistry(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scraper.SetGedungRegistry(registry)
+	}
+	scraper.SetOccupancy(config.AppConfig.OccupancyQueries, config.AppConfig.OccupancyTTL)
+	utils.SetDefaultScraper(scraper)
 
 	// Start server (only runs locally, not on Vercel)
 	port := config.AppConfig.Port
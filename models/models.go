@@ -10,18 +10,73 @@ type Jadwal struct {
 }
 
 type MataKuliah struct {
-	Nama  string `json:"nama"`
-	Waktu string `json:"waktu"`
-	Jam   string `json:"jam"`
-	Ruang string `json:"ruang"`
-	Dosen string `json:"dosen"`
+	Kelas    string  `json:"kelas,omitempty"`
+	Nama     string  `json:"nama"`
+	Waktu    string  `json:"waktu"`
+	Jam      string  `json:"jam"`
+	Periode  []int   `json:"periode"`
+	Mulai    string  `json:"mulai"`
+	Selesai  string  `json:"selesai"`
+	Durasi   int     `json:"durasi"`
+	Ruang    string  `json:"ruang"`
+	Dosen    string  `json:"dosen"`
+	Pengajar []Dosen `json:"pengajar"`
+}
+
+// Dosen is a lecturer parsed from a Dosen cell. Key is the lowercased name
+// without titles, stable across pages.
+type Dosen struct {
+	Nama          string   `json:"nama"`
+	GelarDepan    []string `json:"gelar_depan,omitempty"`
+	GelarBelakang []string `json:"gelar_belakang,omitempty"`
+	Key           string   `json:"key"`
+}
+
+// JadwalKelas is the week of a single class in a search result
+type JadwalKelas struct {
+	Kelas  string `json:"kelas"`
+	Jadwal Jadwal `json:"jadwal"`
+}
+
+// Ruang is a room code broken into its building, floor and room number
+type Ruang struct {
+	Kode   string `json:"kode"`
+	Gedung string `json:"gedung"`
+	Lantai int    `json:"lantai"`
+	Nomor  string `json:"nomor"`
+	Kampus string `json:"kampus,omitempty"`
+	Alamat string `json:"alamat,omitempty"`
+}
+
+type WaktuSlot struct {
+	Periode int    `json:"periode"`
+	Mulai   string `json:"mulai"`
+	Selesai string `json:"selesai"`
 }
 
 type Kegiatan struct {
-	Kegiatan string `json:"kegiatan"`
-	Tanggal  string `json:"tanggal"`
-	Start    string `json:"start"`
-	End      string `json:"end"`
+	Kegiatan   string `json:"kegiatan"`
+	Tanggal    string `json:"tanggal"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	Kategori   string `json:"kategori"`
+	ParseError string `json:"parse_error,omitempty"`
+}
+
+type KegiatanNode struct {
+	Kegiatan
+	Children []KegiatanNode `json:"children,omitempty"`
+}
+
+type KegiatanAktif struct {
+	Kegiatan
+	HariKe   int `json:"hari_ke"`
+	SisaHari int `json:"sisa_hari"`
+}
+
+type KegiatanMendatang struct {
+	Kegiatan
+	MulaiDalam int `json:"mulai_dalam"`
 }
 
 type KelasBaru struct {
@@ -44,9 +99,29 @@ type Response struct {
 	Data   interface{} `json:"data"`
 }
 
+// Ujian holds the fields every exam schedule shares
+type Ujian struct {
+	Nama     string  `json:"nama"`
+	Waktu    string  `json:"waktu"`
+	Hari     string  `json:"hari"`
+	Tanggal  string  `json:"tanggal"`
+	Mulai    string  `json:"mulai"`
+	Selesai  string  `json:"selesai"`
+	Sesi     int     `json:"sesi,omitempty"`
+	Ruang    string  `json:"ruang"`
+	Dosen    string  `json:"dosen"`
+	Pengajar []Dosen `json:"pengajar"`
+}
+
 type UTS struct {
-	Nama  string `json:"nama"`
-	Waktu string `json:"waktu"`
-	Ruang string `json:"ruang"`
-	Dosen string `json:"dosen"`
+	Ujian
+}
+
+type UAS struct {
+	Ujian
+}
+
+// UU is an Ujian Utama entry
+type UU struct {
+	Ujian
 }
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Cassette modes for recording and replaying upstream HTML traffic
+const (
+	CassetteOff    = ""
+	CassetteRecord = "record"
+	CassetteReplay = "replay"
+)
+
+// RecordingFetcher saves every page another fetcher returns to Dir, under
+// the same file names FileFetcher reads, so a recorded directory can be
+// replayed later
+type RecordingFetcher struct {
+	Fetcher Fetcher
+	Dir     string
+}
+
+// NewRecordingFetcher wraps next, creating dir if needed
+func NewRecordingFetcher(next Fetcher, dir string) (*RecordingFetcher, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cassette directory: %v", err)
+	}
+	return &RecordingFetcher{Fetcher: next, Dir: dir}, nil
+}
+
+// Fetch loads the page through the wrapped fetcher and stores a copy.
+// Failing to store it is logged but does not fail the request.
+func (f *RecordingFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	doc, err := f.Fetcher.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := doc.Html()
+	if err != nil {
+		log.Printf("Failed to render cassette for %s: %v", url, err)
+		return doc, nil
+	}
+	path := filepath.Join(f.Dir, FixtureName(url))
+	if err := os.WriteFile(path, []byte(html), 0o644); err != nil {
+		log.Printf("Failed to record cassette %s: %v", path, err)
+	}
+	return doc, nil
+}
+
+func parseDocument(url string, body []byte) (*goquery.Document, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, &ParseError{URL: url, Err: err}
+	}
+	return doc, nil
+}
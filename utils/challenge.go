@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultChallengeRetryAfter is suggested to clients when a challenge page
+// does not carry its own Retry-After header
+const defaultChallengeRetryAfter = 60 * time.Second
+
+// Markers that only appear on Cloudflare interstitial or block pages. The
+// generic /cdn-cgi/challenge-platform script is left out on purpose because
+// Cloudflare injects it into regular pages as well.
+var challengeMarkers = [][]byte{
+	[]byte("<title>Just a moment...</title>"),
+	[]byte("window._cf_chl_opt"),
+	[]byte("cf-browser-verification"),
+	[]byte("Checking your browser before accessing"),
+	[]byte("Attention Required! | Cloudflare"),
+	[]byte(`id="cf-error-details"`),
+}
+
+// isChallengeResponse reports whether an upstream response is a Cloudflare
+// challenge or block page rather than the requested document
+func isChallengeResponse(header http.Header, body []byte) bool {
+	if strings.EqualFold(header.Get("Cf-Mitigated"), "challenge") {
+		return true
+	}
+
+	for _, marker := range challengeMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// challengeRetryAfter reads the Retry-After header in seconds, falling back
+// to defaultChallengeRetryAfter
+func challengeRetryAfter(header http.Header) time.Duration {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultChallengeRetryAfter
+}
+
+// ChallengeStatus describes whether BAAK is currently serving challenge pages
+type ChallengeStatus struct {
+	Challenged bool       `json:"challenged"`
+	Since      *time.Time `json:"since,omitempty"`
+	LastSeen   *time.Time `json:"last_seen,omitempty"`
+	LastURL    string     `json:"last_url,omitempty"`
+	Count      int        `json:"count"`
+}
+
+var (
+	challengeState = ChallengeStatus{}
+	challengeMutex = &sync.RWMutex{}
+)
+
+func recordChallenge(url string) {
+	challengeMutex.Lock()
+	defer challengeMutex.Unlock()
+
+	// The times are replaced rather than updated in place, so snapshots can
+	// share them
+	now := time.Now()
+	if !challengeState.Challenged {
+		since := now
+		challengeState.Challenged = true
+		challengeState.Since = &since
+	}
+	challengeState.LastSeen = &now
+	challengeState.LastURL = url
+	challengeState.Count++
+}
+
+// clearChallenge marks the upstream as reachable again after a real page
+func clearChallenge() {
+	challengeMutex.Lock()
+	defer challengeMutex.Unlock()
+	challengeState.Challenged = false
+	challengeState.Since = nil
+}
+
+// GetChallengeStatus returns a snapshot of the upstream challenge state
+func GetChallengeStatus() ChallengeStatus {
+	challengeMutex.RLock()
+	defer challengeMutex.RUnlock()
+	return challengeState
+}
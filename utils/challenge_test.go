@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const challengePage = `<!DOCTYPE html><html><head><title>Just a moment...</title></head>
+<body><script>window._cf_chl_opt={cType:'managed'};</script></body></html>`
+
+// newChallengeServer serves a Cloudflare interstitial with the given status
+// and counts the requests it receives
+func newChallengeServer(t *testing.T, status int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(challengePage))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(clearChallenge)
+	return server, &hits
+}
+
+func TestFetchDocumentWithRetryChallenge(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		header     http.Header
+		retryAfter string
+	}{
+		{"interstitial 200", http.StatusOK, nil, "60"},
+		{"block 403", http.StatusForbidden, http.Header{"Cf-Mitigated": {"challenge"}, "Retry-After": {"120"}}, "120"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, hits := newChallengeServer(t, tt.status, tt.header)
+
+			_, err := FetchDocumentWithRetry(context.Background(), server.URL+"/jadwal", "", 5)
+
+			var challengeErr *ChallengeError
+			if !errors.As(err, &challengeErr) {
+				t.Fatalf("expected ChallengeError, got %v", err)
+			}
+			if got := atomic.LoadInt32(hits); got != 1 {
+				t.Errorf("made %d attempts, want 1", got)
+			}
+
+			recorder := httptest.NewRecorder()
+			WriteHTTPError(recorder, err)
+			if recorder.Code != http.StatusServiceUnavailable {
+				t.Errorf("status = %d, want 503", recorder.Code)
+			}
+			if got := recorder.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+
+			if !GetChallengeStatus().Challenged {
+				t.Error("challenge status was not recorded")
+			}
+		})
+	}
+}
+
+func TestIsChallengeResponse(t *testing.T) {
+	if isChallengeResponse(http.Header{}, []byte(`<html><script src="/cdn-cgi/challenge-platform/scripts/jsd/main.js"></script><table></table></html>`)) {
+		t.Error("a regular page with the injected challenge-platform script is not a challenge")
+	}
+	if challengeRetryAfter(http.Header{"Retry-After": {"soon"}}) != defaultChallengeRetryAfter {
+		t.Error("an unreadable Retry-After should fall back to the default")
+	}
+	if challengeRetryAfter(http.Header{"Retry-After": {"5"}}) != 5*time.Second {
+		t.Error("Retry-After in seconds was not read")
+	}
+}
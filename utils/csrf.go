@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StatusPageExpired is Laravel's "Page Expired" status, returned when the
+// submitted _token no longer matches the session
+const StatusPageExpired = 419
+
+// DefaultTokenTTL is how long a cached _token is reused before refreshing
+const DefaultTokenTTL = 30 * time.Minute
+
+// sessionGeneration changes every time a new cookie session is established,
+// which invalidates any token issued for the previous session
+var sessionGeneration atomic.Uint64
+
+func newSession() {
+	sessionGeneration.Add(1)
+}
+
+// TokenManager caches the Laravel _token of the current cookie session
+type TokenManager struct {
+	mutex      sync.Mutex
+	ttl        time.Duration
+	token      string
+	fetchedAt  time.Time
+	generation uint64
+}
+
+// NewTokenManager returns a token cache that refreshes tokens older than ttl
+func NewTokenManager(ttl time.Duration) *TokenManager {
+	if ttl <= 0 {
+		ttl = DefaultTokenTTL
+	}
+	return &TokenManager{ttl: ttl}
+}
+
+// cached returns the stored token if it is still valid for this session
+func (tm *TokenManager) cached() (string, bool) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	if tm.token == "" ||
+		time.Since(tm.fetchedAt) > tm.ttl ||
+		tm.generation != sessionGeneration.Load() {
+		return "", false
+	}
+	return tm.token, true
+}
+
+func (tm *TokenManager) store(token string) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm.token = token
+	tm.fetchedAt = time.Now()
+	tm.generation = sessionGeneration.Load()
+}
+
+// Invalidate drops the cached token so the next lookup fetches a fresh one
+func (tm *TokenManager) Invalidate() {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm.token = ""
+}
+
+// Token returns the session's _token, fetching it from the first of
+// pageURLs that carries one when the cache is empty or stale
+func (s *Scraper) Token(ctx context.Context, pageURLs ...string) (string, error) {
+	if token, ok := s.tokens.cached(); ok {
+		return token, nil
+	}
+
+	var lastErr error
+	for _, pageURL := range pageURLs {
+		token, err := s.GetCSRFToken(ctx, pageURL)
+		if err == nil {
+			s.tokens.store(token)
+			return token, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		lastErr = err
+	}
+
+	return "", lastErr
+}
+
+// WithCSRFToken runs search with the session's _token. When BAAK rejects the
+// token with 419 Page Expired, the token is refreshed and search is retried once.
+func (s *Scraper) WithCSRFToken(ctx context.Context, pageURLs []string, search func(token string) error) error {
+	token, err := s.Token(ctx, pageURLs...)
+	if err != nil {
+		return err
+	}
+
+	err = search(token)
+	if !isPageExpired(err) {
+		return err
+	}
+
+	s.tokens.Invalidate()
+	token, err = s.Token(ctx, pageURLs...)
+	if err != nil {
+		return err
+	}
+	return search(token)
+}
+
+func isPageExpired(err error) bool {
+	var statusErr *UpstreamStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == StatusPageExpired
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yafyx/baak-api/config"
+)
+
+// tokenFetcher serves a page with a new _token on every fetch and counts
+// the fetches per URL
+type tokenFetcher struct {
+	mutex   sync.Mutex
+	fetches map[string]int
+	issued  int
+}
+
+func (f *tokenFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.fetches == nil {
+		f.fetches = make(map[string]int)
+	}
+	f.fetches[url]++
+	f.issued++
+	page := fmt.Sprintf(`<form><input type="hidden" name="_token" value="token%d"></form>`, f.issued)
+	return goquery.NewDocumentFromReader(strings.NewReader(page))
+}
+
+func (f *tokenFetcher) count(url string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.fetches[url]
+}
+
+func TestWithCSRFTokenRetriesPageExpired(t *testing.T) {
+	fetcher := &tokenFetcher{}
+	s := NewScraper(fetcher)
+	tokenPage := BaseURL + "/jadwal"
+
+	var tokens []string
+	err := s.WithCSRFToken(context.Background(), []string{tokenPage}, func(token string) error {
+		tokens = append(tokens, token)
+		if len(tokens) == 1 {
+			return &UpstreamStatusError{URL: BaseURL + "/jadwal/cariJadKul", StatusCode: StatusPageExpired}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithCSRFToken failed: %v", err)
+	}
+	if len(tokens) != 2 {
+		t.Fatalf("search ran %d times, want 2", len(tokens))
+	}
+	if tokens[0] == tokens[1] {
+		t.Errorf("retry reused the expired token %q", tokens[0])
+	}
+	if got := fetcher.count(tokenPage); got != 2 {
+		t.Errorf("token page fetched %d times, want 2", got)
+	}
+}
+
+func TestWithCSRFTokenGivesUpAfterOneRetry(t *testing.T) {
+	fetcher := &tokenFetcher{}
+	s := NewScraper(fetcher)
+
+	calls := 0
+	err := s.WithCSRFToken(context.Background(), []string{BaseURL}, func(token string) error {
+		calls++
+		return &UpstreamStatusError{URL: BaseURL, StatusCode: StatusPageExpired}
+	})
+	if !isPageExpired(err) {
+		t.Fatalf("expected the second 419 to be returned, got %v", err)
+	}
+	if calls != 2 || fetcher.count(BaseURL) != 2 {
+		t.Errorf("search ran %d times and token page fetched %d times, want 2 and 2", calls, fetcher.count(BaseURL))
+	}
+}
+
+func TestWithCSRFTokenDoesNotRetryOtherErrors(t *testing.T) {
+	fetcher := &tokenFetcher{}
+	s := NewScraper(fetcher)
+	boom := errors.New("boom")
+
+	calls := 0
+	err := s.WithCSRFToken(context.Background(), []string{BaseURL}, func(token string) error {
+		calls++
+		return boom
+	})
+	if !errors.Is(err, boom) || calls != 1 || fetcher.count(BaseURL) != 1 {
+		t.Errorf("got err %v after %d searches and %d token fetches", err, calls, fetcher.count(BaseURL))
+	}
+}
+
+func TestTokenCacheExpiry(t *testing.T) {
+	fetcher := &tokenFetcher{}
+	s := NewScraper(fetcher)
+	ctx := context.Background()
+
+	first, err := s.Token(ctx, BaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := s.Token(ctx, BaseURL); cached != first || fetcher.count(BaseURL) != 1 {
+		t.Fatalf("a fresh token should be reused, got %q after %d fetches", cached, fetcher.count(BaseURL))
+	}
+
+	// Past the TTL
+	s.tokens.mutex.Lock()
+	s.tokens.fetchedAt = time.Now().Add(-2 * s.tokens.ttl)
+	s.tokens.mutex.Unlock()
+	expired, _ := s.Token(ctx, BaseURL)
+	if expired == first || fetcher.count(BaseURL) != 2 {
+		t.Errorf("an expired token should be refetched, got %q after %d fetches", expired, fetcher.count(BaseURL))
+	}
+
+	// A new cookie session invalidates the token
+	newSession()
+	renewed, _ := s.Token(ctx, BaseURL)
+	if renewed == expired || fetcher.count(BaseURL) != 3 {
+		t.Errorf("a new session should refetch the token, got %q after %d fetches", renewed, fetcher.count(BaseURL))
+	}
+}
+
+func TestWithJadwalSearchUsesConfiguredBaseURL(t *testing.T) {
+	previous := config.AppConfig.BaseURL
+	config.AppConfig.BaseURL = "http://baak.test"
+	defer func() { config.AppConfig.BaseURL = previous }()
+
+	fetcher := &tokenFetcher{}
+	s := NewScraper(fetcher)
+
+	var searchURL string
+	err := s.WithJadwalSearch(context.Background(), "2IA 01", func(u string) error {
+		searchURL = u
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithJadwalSearch failed: %v", err)
+	}
+	if fetcher.count("http://baak.test/jadwal") != 1 {
+		t.Errorf("token page was not fetched from BASE_URL: %v", fetcher.fetches)
+	}
+	if want := "http://baak.test/jadwal/cariJadKul?_token=token1&teks=2IA+01"; searchURL != want {
+		t.Errorf("search URL = %q, want %q", searchURL, want)
+	}
+}
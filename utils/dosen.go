@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+var (
+	// Separators between the lecturers of a team-taught cell
+	dosenSeparator = regexp.MustCompile(`(?i)\s*(?:/|;|&|\n|\bdan\b)\s*`)
+	// Academic titles written before the name. A title ending in a dot may
+	// run straight into the next title or the name, as in "Prof.Dr. Ahmad";
+	// one without a dot must be followed by a space.
+	gelarDepan = regexp.MustCompile(`(?i)^(?:(prof\.|dr\.|drs\.|dra\.|ir\.|drg\.|hj\.|h\.)\s*|(prof|dr|drs|dra|ir)(?:\s+|$))`)
+	// A single academic degree written after the name, with or without
+	// dots, such as "S.Kom.", "SKom", "M.T." or "MMSI"
+	gelarBelakang = regexp.MustCompile(`(?i)^(?:s\.?(?:kom|t|si|e|h|pd|sos|psi|ak)|m\.?(?:kom|t|m|sc|si|e|eng|ak|pd|h|ba)|m\.?m\.?s\.?i|ph\.?d|a\.?md(?:\.?kom)?)\.?$`)
+)
+
+// dosenPlaceholders are keys of cells that name no one in particular
+var dosenPlaceholders = map[string]bool{
+	"team-teaching": true,
+	"tim-teaching":  true,
+	"tim-pengajar":  true,
+	"tim-penguji":   true,
+	"tim-dosen":     true,
+}
+
+// ParseDosen splits a Dosen cell into its lecturers, separating each name
+// from the titles before it ("Dr. Ir.") and after it (", S.Kom., M.T.")
+func ParseDosen(text string) []models.Dosen {
+	dosenList := []models.Dosen{}
+	for _, part := range dosenSeparator.Split(text, -1) {
+		if dosen, ok := parseDosenName(part); ok {
+			dosenList = append(dosenList, dosen)
+		}
+	}
+	return dosenList
+}
+
+func parseDosenName(text string) (models.Dosen, bool) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return models.Dosen{}, false
+	}
+
+	dosen := models.Dosen{}
+	// Everything after the first comma is a trailing title
+	if i := strings.Index(text, ","); i >= 0 {
+		for _, gelar := range strings.Split(text[i+1:], ",") {
+			if gelar = strings.TrimSpace(gelar); gelar != "" {
+				dosen.GelarBelakang = append(dosen.GelarBelakang, gelar)
+			}
+		}
+		text = strings.TrimSpace(text[:i])
+	}
+
+	// Degrees can also follow the name without a comma, as in
+	// "Ahmad Fauzi SKom MMSI"; the first word is always kept
+	words := strings.Fields(text)
+	for len(words) > 1 && gelarBelakang.MatchString(words[len(words)-1]) {
+		dosen.GelarBelakang = append([]string{words[len(words)-1]}, dosen.GelarBelakang...)
+		words = words[:len(words)-1]
+	}
+	text = strings.Join(words, " ")
+
+	for {
+		m := gelarDepan.FindStringSubmatch(text)
+		if m == nil {
+			break
+		}
+		dosen.GelarDepan = append(dosen.GelarDepan, m[1]+m[2])
+		text = strings.TrimSpace(text[len(m[0]):])
+	}
+
+	if text == "" {
+		return models.Dosen{}, false
+	}
+	if strings.ToUpper(text) == text {
+		text = titleCase(text)
+	}
+	dosen.Nama = text
+	dosen.Key = DosenKey(text)
+	if dosen.Key == "" || dosenPlaceholders[dosen.Key] {
+		return models.Dosen{}, false
+	}
+	return dosen, true
+}
+
+// DosenKey returns the canonical key of a name without titles, such as
+// "ahmad-fauzi" for "AHMAD  Fauzi", so the same person matches across pages
+func DosenKey(nama string) string {
+	words := strings.FieldsFunc(strings.ToLower(nama), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
+
+func titleCase(text string) string {
+	words := strings.Fields(strings.ToLower(text))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// matchesDosen reports whether any lecturer's key contains the words of key
+func matchesDosen(dosenList []models.Dosen, key string) bool {
+	for _, dosen := range dosenList {
+		if strings.Contains("-"+dosen.Key+"-", "-"+key+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterJadwalDosen keeps the sessions taught by nama, ordered by start
+// time within each day. cariJadKul also matches course names and rooms, so a
+// search for a lecturer can return rows that are not theirs. Titles in nama
+// are ignored.
+func FilterJadwalDosen(jadwal models.Jadwal, nama string) models.Jadwal {
+	key := ""
+	if dosen, ok := parseDosenName(nama); ok {
+		key = dosen.Key
+	}
+
+	filtered := models.Jadwal{}
+	if key == "" {
+		return filtered
+	}
+	for hari, sessions := range jadwalDays(&jadwal) {
+		for _, mataKuliah := range *sessions {
+			if matchesDosen(mataKuliah.Pengajar, key) {
+				addToJadwal(&filtered, hari, mataKuliah)
+			}
+		}
+	}
+
+	for _, sessions := range jadwalDays(&filtered) {
+		sort.SliceStable(*sessions, func(i, j int) bool {
+			return (*sessions)[i].Mulai < (*sessions)[j].Mulai
+		})
+	}
+	return filtered
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+func TestParseDosen(t *testing.T) {
+	tests := map[string][]models.Dosen{
+		"Dr. Ir. Budi Santoso, M.Kom.": {
+			{Nama: "Budi Santoso", GelarDepan: []string{"Dr.", "Ir."}, GelarBelakang: []string{"M.Kom."}, Key: "budi-santoso"},
+		},
+		"Ahmad Fauzi, S.Kom., M.T. / Siti Rahma, S.Si.": {
+			{Nama: "Ahmad Fauzi", GelarBelakang: []string{"S.Kom.", "M.T."}, Key: "ahmad-fauzi"},
+			{Nama: "Siti Rahma", GelarBelakang: []string{"S.Si."}, Key: "siti-rahma"},
+		},
+		"AHMAD  FAUZI dan Prof. Irawan": {
+			{Nama: "Ahmad Fauzi", Key: "ahmad-fauzi"},
+			{Nama: "Irawan", GelarDepan: []string{"Prof."}, Key: "irawan"},
+		},
+		"Prof.Dr. Ahmad Fauzi, M.T.": {
+			{Nama: "Ahmad Fauzi", GelarDepan: []string{"Prof.", "Dr."}, GelarBelakang: []string{"M.T."}, Key: "ahmad-fauzi"},
+		},
+		"Dr.Ir.Budi Santoso": {
+			{Nama: "Budi Santoso", GelarDepan: []string{"Dr.", "Ir."}, Key: "budi-santoso"},
+		},
+		"Drs Irawan": {
+			{Nama: "Irawan", GelarDepan: []string{"Drs"}, Key: "irawan"},
+		},
+		"Ahmad Fauzi S.Kom., M.T.": {
+			{Nama: "Ahmad Fauzi", GelarBelakang: []string{"S.Kom.", "M.T."}, Key: "ahmad-fauzi"},
+		},
+		"Ahmad Fauzi SKom MMSI": {
+			{Nama: "Ahmad Fauzi", GelarBelakang: []string{"SKom", "MMSI"}, Key: "ahmad-fauzi"},
+		},
+		"Siti Rahma M.Sc, Ph.D": {
+			{Nama: "Siti Rahma", GelarBelakang: []string{"M.Sc", "Ph.D"}, Key: "siti-rahma"},
+		},
+		"TEAM TEACHING": {},
+		"":              {},
+	}
+	for text, want := range tests {
+		if got := ParseDosen(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseDosen(%q) = %+v, want %+v", text, got, want)
+		}
+	}
+}
+
+func TestFilterJadwalDosen(t *testing.T) {
+	jadwal := models.Jadwal{
+		Senin: []models.MataKuliah{
+			{Nama: "Struktur Data", Mulai: "10:30", Pengajar: ParseDosen("Ahmad Fauzi, S.Kom. / Siti Rahma")},
+			{Nama: "Fauzi Lab", Mulai: "07:30", Pengajar: ParseDosen("Budi Santoso")},
+			{Nama: "Basis Data", Mulai: "07:30", Pengajar: ParseDosen("AHMAD FAUZI")},
+		},
+	}
+
+	filtered := FilterJadwalDosen(jadwal, "Prof.Dr. Ahmad Fauzi, M.T.")
+	if len(filtered.Senin) != 2 || filtered.Senin[0].Nama != "Basis Data" || filtered.Senin[1].Nama != "Struktur Data" {
+		t.Fatalf("unexpected filtered jadwal: %+v", filtered.Senin)
+	}
+}
@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Parser status values reported on /health
+const (
+	ParserStatusUnknown = "unknown"
+	ParserStatusOK      = "ok"
+	ParserStatusDrift   = "drift"
+)
+
+// DriftEvent records a page whose shape did not match what a parser expects
+type DriftEvent struct {
+	Time    time.Time `json:"time"`
+	URL     string    `json:"url"`
+	Reasons []string  `json:"reasons"`
+}
+
+// ParserStatus is the latest shape check result of a single parser
+type ParserStatus struct {
+	Parser     string      `json:"parser"`
+	Status     string      `json:"status"`
+	LastCheck  *time.Time  `json:"last_check,omitempty"`
+	DriftCount int         `json:"drift_count"`
+	LastDrift  *DriftEvent `json:"last_drift,omitempty"`
+}
+
+var (
+	parserStatuses = map[string]*ParserStatus{}
+	parserMutex    = &sync.RWMutex{}
+)
+
+func init() {
+	for _, parser := range []string{"jadwal", "kegiatan", "kelasbaru", "mahasiswabaru", "uas", "uts", "uu", "waktu_kuliah", "waktu_ujian"} {
+		parserStatuses[parser] = &ParserStatus{Parser: parser, Status: ParserStatusUnknown}
+	}
+}
+
+// GetParserStatuses returns a snapshot of every parser's status, sorted by name
+func GetParserStatuses() []ParserStatus {
+	parserMutex.RLock()
+	defer parserMutex.RUnlock()
+
+	statuses := make([]ParserStatus, 0, len(parserStatuses))
+	for _, status := range parserStatuses {
+		snapshot := *status
+		if status.LastDrift != nil {
+			event := *status.LastDrift
+			snapshot.LastDrift = &event
+		}
+		statuses = append(statuses, snapshot)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Parser < statuses[j].Parser
+	})
+	return statuses
+}
+
+// shapeCheck collects the reasons a page does not look the way a parser
+// expects and records the outcome once parsing is done
+type shapeCheck struct {
+	parser  string
+	url     string
+	reasons []string
+}
+
+func newShapeCheck(parser, url string) *shapeCheck {
+	return &shapeCheck{parser: parser, url: url}
+}
+
+func (c *shapeCheck) addf(format string, args ...interface{}) {
+	c.reasons = append(c.reasons, fmt.Sprintf(format, args...))
+}
+
+// requireTable flags pages without the result table
+func (c *shapeCheck) requireTable(table *goquery.Selection) bool {
+	if table.Length() == 0 {
+		c.addf("table not found")
+		return false
+	}
+	return true
+}
+
+// checkColumns flags header rows that do not match the expected columns
+func (c *shapeCheck) checkColumns(table Table) {
+	if !table.HeaderFound {
+		c.addf("header row not found")
+	}
+	if len(table.Missing) > 0 {
+		c.addf("missing columns: %v", table.Missing)
+	}
+	if len(table.Unexpected) > 0 {
+		c.addf("unexpected columns: %v", table.Unexpected)
+	}
+}
+
+// checkExtracted runs the column and row checks on an extracted table
+func (c *shapeCheck) checkExtracted(sel *goquery.Selection, table Table) {
+	c.checkColumns(table)
+	c.checkRows(len(table.Rows), 0, countDataRows(sel))
+}
+
+// checkTableAt checks another table of the same page, prefixing its
+// reasons with the table's position
+func (c *shapeCheck) checkTableAt(n int, sel *goquery.Selection, table Table) {
+	sub := newShapeCheck(c.parser, c.url)
+	sub.checkExtracted(sel, table)
+	for _, reason := range sub.reasons {
+		c.addf("table %d: %s", n, reason)
+	}
+}
+
+// checkRows flags pages that yield fewer than min entries, or none at all
+// even though the table has data rows
+func (c *shapeCheck) checkRows(parsed, min, dataRows int) {
+	if parsed < min {
+		c.addf("parsed %d rows, expected at least %d", parsed, min)
+	} else if parsed == 0 && dataRows > 0 {
+		c.addf("table has %d data rows but none were parsed", dataRows)
+	}
+}
+
+// done stores the check result and logs drift as a structured line
+func (c *shapeCheck) done() {
+	now := time.Now()
+
+	parserMutex.Lock()
+	status, ok := parserStatuses[c.parser]
+	if !ok {
+		status = &ParserStatus{Parser: c.parser}
+		parserStatuses[c.parser] = status
+	}
+	checked := now
+	status.LastCheck = &checked
+	if len(c.reasons) == 0 {
+		status.Status = ParserStatusOK
+		parserMutex.Unlock()
+		return
+	}
+
+	event := DriftEvent{Time: now, URL: redactToken(c.url), Reasons: c.reasons}
+	status.Status = ParserStatusDrift
+	status.DriftCount++
+	status.LastDrift = &event
+	parserMutex.Unlock()
+
+	line, _ := json.Marshal(struct {
+		Event   string   `json:"event"`
+		Parser  string   `json:"parser"`
+		URL     string   `json:"url"`
+		Reasons []string `json:"reasons"`
+	}{"parser_drift", c.parser, event.URL, event.Reasons})
+	log.Println(string(line))
+}
+
+// countDataRows counts the rows of table that are as wide as its header, so
+// message rows such as a colspan "Data tidak ditemukan" are not data rows.
+// Without a th header the widest row sets the width.
+func countDataRows(table *goquery.Selection) int {
+	rows := table.Find("tr")
+
+	width := 0
+	rows.EachWithBreak(func(_ int, row *goquery.Selection) bool {
+		width = row.Find("th").Length()
+		return width == 0
+	})
+	if width == 0 {
+		rows.Each(func(_ int, row *goquery.Selection) {
+			if cells := row.Find("td").Length(); cells > width {
+				width = cells
+			}
+		})
+	}
+
+	return rows.FilterFunction(func(_ int, row *goquery.Selection) bool {
+		cells := row.Find("td").Length()
+		return cells > 0 && cells >= width
+	}).Length()
+}
+
+// redactToken removes the session _token from a URL before it is logged
+func redactToken(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	if query.Has("_token") {
+		query.Del("_token")
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// pageFetcher serves inline pages for some URLs and falls back to another
+// Fetcher for the rest
+type pageFetcher struct {
+	Fetcher
+	pages map[string]string
+}
+
+func (f pageFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	if page, ok := f.pages[url]; ok {
+		return goquery.NewDocumentFromReader(strings.NewReader(page))
+	}
+	return f.Fetcher.Fetch(ctx, url)
+}
+
+func parserStatus(t *testing.T, parser string) ParserStatus {
+	t.Helper()
+	for _, status := range GetParserStatuses() {
+		if status.Parser == parser {
+			return status
+		}
+	}
+	t.Fatalf("no status for parser %q", parser)
+	return ParserStatus{}
+}
+
+func TestCountDataRowsSkipsMessageRows(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<table>
+		<tr><th>No</th><th>Nama</th><th>Kelas</th></tr>
+		<tr><td colspan="3">Data tidak ditemukan</td></tr>
+	</table>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := countDataRows(doc.Find("table")); got != 0 {
+		t.Errorf("countDataRows = %d, want 0", got)
+	}
+}
+
+func TestEmptyJadwalIsNotDrift(t *testing.T) {
+	before := parserStatus(t, "jadwal").DriftCount
+
+	jadwal, err := newFixtureScraper().GetJadwal(context.Background(), BaseURL+"/jadwal/cariJadKul?_token=abc&teks=9ZZ99")
+	if err != nil {
+		t.Fatalf("GetJadwal failed: %v", err)
+	}
+	if len(jadwal.Senin) != 0 {
+		t.Errorf("expected an empty schedule, got %+v", jadwal)
+	}
+
+	status := parserStatus(t, "jadwal")
+	if status.DriftCount != before || status.Status != ParserStatusOK {
+		t.Errorf("empty result recorded drift: %+v", status)
+	}
+}
+
+func TestJadwalChecksEveryTable(t *testing.T) {
+	url := BaseURL + "/jadwal/cariJadKul?_token=abc&teks=2IA0X"
+	page := `<html><body>
+		<h4>Kelas 2IA01</h4>
+		<table>
+			<tr><th>No</th><th>Hari</th><th>Mata Kuliah</th><th>Waktu</th><th>Ruang</th><th>Dosen</th></tr>
+			<tr><td>1</td><td>Senin</td><td>Struktur Data</td><td>1/2</td><td>D462</td><td>Ahmad Fauzi</td></tr>
+		</table>
+		<h4>Kelas 2IA02</h4>
+		<table>
+			<tr><th>No</th><th>Hari</th><th>Mata Kuliah</th><th>Waktu</th><th>Dosen</th></tr>
+			<tr><td>1</td><td>Selasa</td><td>Basis Data</td><td>3/4</td><td>Siti Rahma</td></tr>
+		</table>
+	</body></html>`
+	s := NewScraper(pageFetcher{Fetcher: NewFileFetcher("testdata"), pages: map[string]string{url: page}})
+	before := parserStatus(t, "jadwal").DriftCount
+
+	if _, err := s.GetJadwal(context.Background(), url); err != nil {
+		t.Fatalf("GetJadwal failed: %v", err)
+	}
+
+	status := parserStatus(t, "jadwal")
+	if status.DriftCount != before+1 || status.LastDrift == nil {
+		t.Fatalf("expected drift from the second table, got %+v", status)
+	}
+	if reasons := strings.Join(status.LastDrift.Reasons, "; "); !strings.Contains(reasons, "table 2: missing columns: [Ruang]") {
+		t.Errorf("unexpected drift reasons: %s", reasons)
+	}
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// UpstreamStatusError reports a non-200 response from BAAK
+type UpstreamStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *UpstreamStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// ParseError reports a BAAK page that could not be read or parsed
+type ParseError struct {
+	URL string
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.URL, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// CSRFTokenMissingError reports a page that did not contain the _token input
+type CSRFTokenMissingError struct {
+	URL string
+}
+
+func (e *CSRFTokenMissingError) Error() string {
+	return "CSRF token input field not found on page: " + e.URL
+}
+
+// RuangQueryError reports a free-room query for a day or period that has
+// no lectures
+type RuangQueryError struct {
+	Message string
+}
+
+func (e *RuangQueryError) Error() string {
+	return e.Message
+}
+
+// TimeoutError reports an upstream fetch that ran out of time, either because
+// the request deadline passed or because BAAK stopped responding
+type TimeoutError struct {
+	URL string
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.URL == "" {
+		return fmt.Sprintf("upstream timeout: %v", e.Err)
+	}
+	return fmt.Sprintf("upstream timeout fetching %s: %v", e.URL, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// UnreachableError reports that BAAK could not be reached at all, such as a
+// DNS failure, a refused connection or a session that could not be set up
+type UnreachableError struct {
+	URL string
+	Err error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("upstream unreachable at %s: %v", e.URL, e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// ChallengeError reports a Cloudflare challenge or block page served in
+// place of the requested document
+type ChallengeError struct {
+	URL        string
+	RetryAfter time.Duration
+}
+
+func (e *ChallengeError) Error() string {
+	return "upstream challenge page served for " + e.URL
+}
+
+// isTimeout reports whether err is a network or context timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
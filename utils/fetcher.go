@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yafyx/baak-api/config"
+)
+
+// Fetcher retrieves and parses a BAAK page
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (*goquery.Document, error)
+}
+
+// HTTPFetcher fetches pages from the live BAAK site with retries
+type HTTPFetcher struct{}
+
+// Fetch downloads the page through FetchDocument
+func (HTTPFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	return FetchDocument(ctx, url)
+}
+
+// FileFetcher serves pages from saved HTML files instead of the network
+type FileFetcher struct {
+	Dir string
+}
+
+// NewFileFetcher returns a fetcher that reads fixtures from dir
+func NewFileFetcher(dir string) *FileFetcher {
+	return &FileFetcher{Dir: dir}
+}
+
+// Fetch loads the fixture stored for the given URL
+func (f *FileFetcher) Fetch(ctx context.Context, rawURL string) (*goquery.Document, error) {
+	if ctx.Err() != nil {
+		return nil, canceledError(ctx)
+	}
+
+	path := filepath.Join(f.Dir, FixtureName(rawURL))
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture for %s: %w", rawURL, err)
+	}
+	defer file.Close()
+
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		return nil, &ParseError{URL: rawURL, Err: err}
+	}
+
+	return doc, nil
+}
+
+// NewFetcher picks the fetcher backend described by the configuration.
+// FIXTURE_DIR and cassette replay both serve saved pages; recording wraps
+// the live fetcher.
+func NewFetcher(cfg config.Config) (Fetcher, error) {
+	switch cfg.CassetteMode {
+	case CassetteOff:
+	case CassetteReplay:
+		return NewFileFetcher(cfg.CassetteDir), nil
+	case CassetteRecord:
+		if cfg.FixtureDir != "" {
+			return nil, fmt.Errorf("cassette recording cannot be combined with FIXTURE_DIR")
+		}
+		return NewRecordingFetcher(HTTPFetcher{}, cfg.CassetteDir)
+	default:
+		return nil, fmt.Errorf("unknown cassette mode: %q", cfg.CassetteMode)
+	}
+
+	if cfg.FixtureDir != "" {
+		return NewFileFetcher(cfg.FixtureDir), nil
+	}
+	return HTTPFetcher{}, nil
+}
+
+var unsafeFixtureChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FixtureName maps a BAAK URL to the file name its saved page is stored under.
+// The host and the per-session _token parameter are ignored and the remaining
+// query parameters are sorted, so the same search always maps to the same file.
+func FixtureName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return unsafeFixtureChars.ReplaceAllString(rawURL, "_") + ".html"
+	}
+
+	name := strings.Trim(u.Path, "/")
+	if name == "" {
+		name = "index"
+	}
+	name = strings.ReplaceAll(name, "/", "_")
+
+	query := u.Query()
+	query.Del("_token")
+	for key, values := range query {
+		if len(values) == 1 && values[0] == "" {
+			query.Del(key)
+		}
+	}
+	if encoded := query.Encode(); encoded != "" {
+		name += "__" + encoded
+	}
+
+	return unsafeFixtureChars.ReplaceAllString(name, "_") + ".html"
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yafyx/baak-api/config"
+)
+
+func TestFixtureName(t *testing.T) {
+	want := "jadwal_cariJadKul__teks_2IA01.html"
+	urls := []string{
+		BaseURL + "/jadwal/cariJadKul?_token=abc&teks=2IA01",
+		BaseURL + "/jadwal/cariJadKul?teks=2IA01&_token=xyz",
+		"http://localhost:8080/jadwal/cariJadKul?teks=2IA01",
+		BaseURL + "/jadwal/cariJadKul?&teks=2IA01",
+	}
+	for _, url := range urls {
+		if got := FixtureName(url); got != want {
+			t.Errorf("FixtureName(%q) = %q, want %q", url, got, want)
+		}
+	}
+
+	a := FixtureName(BaseURL + "/cariKelasBaru?_token=abc&tipeKelasBaru=Kelas&teks=3IA01&page=2")
+	b := FixtureName(BaseURL + "/cariKelasBaru?page=2&teks=3IA01&tipeKelasBaru=Kelas&_token=def")
+	if a != b || a != "cariKelasBaru__page_2_teks_3IA01_tipeKelasBaru_Kelas.html" {
+		t.Errorf("query order changed the fixture name: %q, %q", a, b)
+	}
+	if got := FixtureName(BaseURL); got != "index.html" {
+		t.Errorf("FixtureName(BaseURL) = %q, want index.html", got)
+	}
+}
+
+func TestRecordingFetcherReplay(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecordingFetcher(NewFileFetcher("testdata"), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	url := BaseURL + "/jadwal?_token=abc"
+	if _, err := NewScraper(recorder).GetCSRFToken(ctx, url); err != nil {
+		t.Fatalf("recording failed: %v", err)
+	}
+
+	replay, err := NewFetcher(config.Config{CassetteMode: CassetteReplay, CassetteDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := NewScraper(replay).GetCSRFToken(ctx, BaseURL+"/jadwal")
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if token != "fixturetoken0123456789abcdefghijklmnopqrstu" {
+		t.Errorf("unexpected replayed token %q", token)
+	}
+}
+
+func TestNewFetcherRejectsUnknownMode(t *testing.T) {
+	if _, err := NewFetcher(config.Config{CassetteMode: "rewind"}); err == nil {
+		t.Error("expected an error for an unknown cassette mode")
+	}
+}
@@ -0,0 +1,242 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yafyx/baak-api/models"
+)
+
+// kalenderRow is a row of the kalender table. Rows that do not have the
+// kegiatan/tanggal layout are kept as separators because they end a group
+// of lettered sub-items.
+type kalenderRow struct {
+	kegiatan  string
+	tanggal   string
+	separator bool
+}
+
+// getKalenderRows fetches the kalender table and checks its shape
+func (s *Scraper) getKalenderRows(ctx context.Context, url string) ([]kalenderRow, error) {
+	doc, err := s.fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []kalenderRow
+	entries := 0
+
+	tableSel := doc.Find("table").First()
+	check := newShapeCheck("kegiatan", url)
+	defer check.done()
+	if check.requireTable(tableSel) {
+		defer func() {
+			check.checkRows(entries, 1, countDataRows(tableSel))
+		}()
+	}
+
+	tableSel.Find("tr").Each(func(i int, row *goquery.Selection) {
+		cells := row.Find("td")
+		if cells.Length() != 2 {
+			rows = append(rows, kalenderRow{separator: true})
+			return
+		}
+
+		rows = append(rows, kalenderRow{
+			kegiatan: strings.TrimSpace(cells.Eq(0).Text()),
+			tanggal:  strings.TrimSpace(cells.Eq(1).Text()),
+		})
+		entries++
+	})
+
+	return rows, nil
+}
+
+// GetKegiatan returns the kalender as a flat list. Lettered sub-items are
+// prefixed with the undated activity they belong to.
+func (s *Scraper) GetKegiatan(ctx context.Context, url string) ([]models.Kegiatan, error) {
+	rows, err := s.getKalenderRows(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var kegiatanList []models.Kegiatan
+	var parentKegiatan string
+
+	for _, row := range rows {
+		if row.separator {
+			parentKegiatan = ""
+			continue
+		}
+
+		if row.tanggal == "" {
+			parentKegiatan = row.kegiatan
+			continue
+		}
+
+		fullKegiatan := row.kegiatan
+		if parentKegiatan != "" && isSubItem(row.kegiatan) {
+			fullKegiatan = parentKegiatan + " " + row.kegiatan
+		} else {
+			parentKegiatan = ""
+		}
+
+		kegiatan := newKegiatan(row)
+		kegiatan.Kegiatan = fullKegiatan
+		kegiatan.Kategori = s.kategori.classify(fullKegiatan)
+		kegiatanList = append(kegiatanList, kegiatan)
+	}
+
+	return kegiatanList, nil
+}
+
+// GetKegiatanTree returns the kalender with lettered sub-items nested under
+// the activity they follow. Every node carries only its own dates, so a
+// parent without a date of its own has empty start and end.
+func (s *Scraper) GetKegiatanTree(ctx context.Context, url string) ([]models.KegiatanNode, error) {
+	rows, err := s.getKalenderRows(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree []models.KegiatanNode
+	parent := -1
+
+	for _, row := range rows {
+		if row.separator {
+			parent = -1
+			continue
+		}
+
+		node := models.KegiatanNode{Kegiatan: newKegiatan(row)}
+		node.Kategori = s.kategori.classify(row.kegiatan)
+		if parent >= 0 && isSubItem(row.kegiatan) {
+			// Sub-items such as "a. Gelombang I" rarely name their category
+			if node.Kategori == KategoriLainnya {
+				node.Kategori = tree[parent].Kategori
+			}
+			tree[parent].Children = append(tree[parent].Children, node)
+			continue
+		}
+
+		tree = append(tree, node)
+		parent = len(tree) - 1
+	}
+
+	return tree, nil
+}
+
+// newKegiatan builds a kalender entry, parsing its date cell when present
+func newKegiatan(row kalenderRow) models.Kegiatan {
+	kegiatan := models.Kegiatan{
+		Kegiatan: row.kegiatan,
+		Tanggal:  row.tanggal,
+	}
+	if row.tanggal == "" {
+		return kegiatan
+	}
+
+	start, end, parseError := parseKegiatanTanggal(row.tanggal)
+	if parseError != "" {
+		log.Printf("[kegiatan] could not parse date %q of %q: %s", row.tanggal, row.kegiatan, parseError)
+	}
+	kegiatan.Start = start
+	kegiatan.End = end
+	kegiatan.ParseError = parseError
+	return kegiatan
+}
+
+func isSubItem(text string) bool {
+	return regexp.MustCompile(`^[a-z]\..+`).MatchString(text)
+}
+
+// parseKegiatanTanggal converts a kalender date cell into ISO start and end
+// dates, returning a description of the problem when it cannot be parsed
+func parseKegiatanTanggal(tanggal string) (start, end, parseError string) {
+	startDate, endDate, err := ParseTanggalRange(tanggal)
+	if err != nil {
+		return "", "", err.Error()
+	}
+	return startDate.Format(ISODate), endDate.Format(ISODate), ""
+}
+
+// JakartaLocation returns Asia/Jakarta, falling back to a fixed UTC+7 zone
+// when the time zone database is not available
+func JakartaLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// TodayJakarta returns today's date in Asia/Jakarta as a UTC midnight, the
+// form ParseTanggalRange produces
+func TodayJakarta() time.Time {
+	now := time.Now().In(JakartaLocation())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// kegiatanDates returns the parsed start and end of an activity
+func kegiatanDates(kegiatan models.Kegiatan) (start, end time.Time, ok bool) {
+	start, err := time.Parse(ISODate, kegiatan.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err = time.Parse(ISODate, kegiatan.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// KegiatanAktifPada returns the activities running on date, with how far
+// into them and how many days are left
+func KegiatanAktifPada(kegiatanList []models.Kegiatan, date time.Time) []models.KegiatanAktif {
+	aktif := []models.KegiatanAktif{}
+	for _, kegiatan := range kegiatanList {
+		start, end, ok := kegiatanDates(kegiatan)
+		if !ok || date.Before(start) || date.After(end) {
+			continue
+		}
+		aktif = append(aktif, models.KegiatanAktif{
+			Kegiatan: kegiatan,
+			HariKe:   daysBetween(start, date) + 1,
+			SisaHari: daysBetween(date, end),
+		})
+	}
+	return aktif
+}
+
+// KegiatanBerikutnya returns up to limit activities starting after date,
+// soonest first, with the number of days until each one starts
+func KegiatanBerikutnya(kegiatanList []models.Kegiatan, date time.Time, limit int) []models.KegiatanMendatang {
+	mendatang := []models.KegiatanMendatang{}
+	for _, kegiatan := range kegiatanList {
+		start, _, ok := kegiatanDates(kegiatan)
+		if !ok || !start.After(date) {
+			continue
+		}
+		mendatang = append(mendatang, models.KegiatanMendatang{
+			Kegiatan:   kegiatan,
+			MulaiDalam: daysBetween(date, start),
+		})
+	}
+
+	sort.SliceStable(mendatang, func(i, j int) bool {
+		return mendatang[i].MulaiDalam < mendatang[j].MulaiDalam
+	})
+	if limit > 0 && len(mendatang) > limit {
+		mendatang = mendatang[:limit]
+	}
+	return mendatang
+}
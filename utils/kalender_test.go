@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+func TestKegiatanAktifDanBerikutnya(t *testing.T) {
+	kegiatanList := []models.Kegiatan{
+		{Kegiatan: "UTS", Start: "2025-04-28", End: "2025-05-03"},
+		{Kegiatan: "Wisuda", Start: "2025-05-20", End: "2025-05-20"},
+		{Kegiatan: "KRS", Start: "2025-05-05", End: "2025-05-09"},
+		{Kegiatan: "Akan diumumkan", ParseError: "unrecognized date"},
+	}
+	date := time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC)
+
+	aktif := KegiatanAktifPada(kegiatanList, date)
+	if len(aktif) != 1 || aktif[0].Kegiatan.Kegiatan != "UTS" || aktif[0].HariKe != 3 || aktif[0].SisaHari != 3 {
+		t.Fatalf("unexpected active activities: %+v", aktif)
+	}
+
+	mendatang := KegiatanBerikutnya(kegiatanList, date, 1)
+	if len(mendatang) != 1 || mendatang[0].Kegiatan.Kegiatan != "KRS" || mendatang[0].MulaiDalam != 5 {
+		t.Fatalf("unexpected upcoming activities: %+v", mendatang)
+	}
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+// Kalender categories
+const (
+	KategoriLibur       = "libur"
+	KategoriUjian       = "ujian"
+	KategoriPendaftaran = "pendaftaran"
+	KategoriPerkuliahan = "perkuliahan"
+	KategoriWisuda      = "wisuda"
+	KategoriLainnya     = "lainnya"
+)
+
+// KategoriRule assigns Kategori to activities whose text contains any of the
+// keywords as a whole word, ignoring case. Rules are tried in order and the
+// first match wins.
+type KategoriRule struct {
+	Kategori string   `json:"kategori"`
+	Keywords []string `json:"keywords"`
+}
+
+// DefaultKategoriRules are used when no rules file is configured. Holidays
+// and graduation come first so "Libur Ujian" or "Wisuda Ujian Utama" are not
+// filed as exams.
+var DefaultKategoriRules = []KategoriRule{
+	{Kategori: KategoriLibur, Keywords: []string{"libur", "cuti bersama", "hari raya"}},
+	{Kategori: KategoriWisuda, Keywords: []string{"wisuda"}},
+	{Kategori: KategoriUjian, Keywords: []string{"ujian", "uts", "uas", "uu"}},
+	{Kategori: KategoriPendaftaran, Keywords: []string{"pendaftaran", "registrasi", "her-registrasi", "daftar ulang", "krs"}},
+	{Kategori: KategoriPerkuliahan, Keywords: []string{"perkuliahan", "kuliah"}},
+}
+
+// kategoriMatcher is a compiled set of KategoriRule
+type kategoriMatcher struct {
+	kategori []string
+	patterns []*regexp.Regexp
+}
+
+func newKategoriMatcher(rules []KategoriRule) *kategoriMatcher {
+	matcher := &kategoriMatcher{}
+	for _, rule := range rules {
+		if rule.Kategori == "" || len(rule.Keywords) == 0 {
+			continue
+		}
+		keywords := make([]string, 0, len(rule.Keywords))
+		for _, keyword := range rule.Keywords {
+			keywords = append(keywords, regexp.QuoteMeta(strings.ToLower(strings.TrimSpace(keyword))))
+		}
+		pattern := regexp.MustCompile(`(?:^|[^\pL\pN])(?:` + strings.Join(keywords, "|") + `)(?:$|[^\pL\pN])`)
+		matcher.kategori = append(matcher.kategori, rule.Kategori)
+		matcher.patterns = append(matcher.patterns, pattern)
+	}
+	return matcher
+}
+
+// classify returns the category of the first matching rule, or KategoriLainnya
+func (m *kategoriMatcher) classify(text string) string {
+	text = strings.ToLower(text)
+	for i, pattern := range m.patterns {
+		if pattern.MatchString(text) {
+			return m.kategori[i]
+		}
+	}
+	return KategoriLainnya
+}
+
+// LoadKategoriRules reads rules from a JSON file holding a list of
+// {"kategori": ..., "keywords": [...]} objects
+func LoadKategoriRules(path string) ([]KategoriRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kategori rules: %v", err)
+	}
+
+	var rules []KategoriRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse kategori rules: %v", err)
+	}
+	return rules, nil
+}
+
+// SetKategoriRules replaces the rules the scraper classifies activities with
+func (s *Scraper) SetKategoriRules(rules []KategoriRule) {
+	s.kategori = newKategoriMatcher(rules)
+}
+
+// FilterKegiatan keeps the activities of the given category
+func FilterKegiatan(kegiatanList []models.Kegiatan, kategori string) []models.Kegiatan {
+	filtered := []models.Kegiatan{}
+	for _, kegiatan := range kegiatanList {
+		if kegiatan.Kategori == kategori {
+			filtered = append(filtered, kegiatan)
+		}
+	}
+	return filtered
+}
+
+// FilterKegiatanTree keeps nodes of the given category, along with parents
+// of another category that still have matching children
+func FilterKegiatanTree(tree []models.KegiatanNode, kategori string) []models.KegiatanNode {
+	filtered := []models.KegiatanNode{}
+	for _, node := range tree {
+		children := FilterKegiatanTree(node.Children, kategori)
+		if node.Kategori != kategori && len(children) == 0 {
+			continue
+		}
+		if node.Kategori != kategori {
+			node.Children = children
+		}
+		filtered = append(filtered, node)
+	}
+	return filtered
+}
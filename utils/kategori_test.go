@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+func TestKategoriMatcherCustomRules(t *testing.T) {
+	matcher := newKategoriMatcher([]KategoriRule{
+		{Kategori: "seminar", Keywords: []string{"Seminar", "kuliah umum"}},
+	})
+
+	tests := map[string]string{
+		"Seminar Nasional Teknologi": "seminar",
+		"Kuliah Umum Rektor":         "seminar",
+		"Perkuliahan":                KategoriLainnya,
+	}
+	for text, want := range tests {
+		if got := matcher.classify(text); got != want {
+			t.Errorf("classify(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestFilterKegiatanTree(t *testing.T) {
+	tree := []models.KegiatanNode{
+		{Kegiatan: models.Kegiatan{Kegiatan: "Perkuliahan", Kategori: KategoriPerkuliahan}},
+		{
+			Kegiatan: models.Kegiatan{Kegiatan: "Kegiatan Semester", Kategori: KategoriLainnya},
+			Children: []models.KegiatanNode{
+				{Kegiatan: models.Kegiatan{Kegiatan: "a. UTS", Kategori: KategoriUjian}},
+				{Kegiatan: models.Kegiatan{Kegiatan: "b. Wisuda", Kategori: KategoriWisuda}},
+			},
+		},
+	}
+
+	filtered := FilterKegiatanTree(tree, KategoriUjian)
+	if len(filtered) != 1 || filtered[0].Kegiatan.Kegiatan != "Kegiatan Semester" {
+		t.Fatalf("unexpected filtered tree: %+v", filtered)
+	}
+	if len(filtered[0].Children) != 1 || filtered[0].Children[0].Kegiatan.Kegiatan != "a. UTS" {
+		t.Errorf("unexpected children: %+v", filtered[0].Children)
+	}
+}
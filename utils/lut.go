@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+// DefaultTimeSlotTTL is how long a kuliahUjian time-slot table is served
+// before it is refreshed in the background
+const DefaultTimeSlotTTL = 6 * time.Hour
+
+// timeSlotRefreshTimeout bounds a background refresh of a time-slot table
+const timeSlotRefreshTimeout = 45 * time.Second
+
+// timeSlotRetryInterval is how long a stale table waits after a refresh
+// attempt before another one is started
+const timeSlotRetryInterval = 5 * time.Minute
+
+// timeSlotCache keeps the period→time tables in memory, keyed by their
+// kuliahUjian number. Once loaded, stale data keeps being served while a
+// single background refresh runs, and it is kept when that refresh fails.
+// Failed refreshes are retried at most once per retryInterval.
+type timeSlotCache struct {
+	mutex         sync.Mutex
+	ttl           time.Duration
+	retryInterval time.Duration
+	entries       map[int]*timeSlotEntry
+}
+
+type timeSlotEntry struct {
+	slots       []models.WaktuSlot
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	refreshing  bool
+}
+
+func newTimeSlotCache(ttl time.Duration) *timeSlotCache {
+	if ttl <= 0 {
+		ttl = DefaultTimeSlotTTL
+	}
+	return &timeSlotCache{
+		ttl:           ttl,
+		retryInterval: timeSlotRetryInterval,
+		entries:       make(map[int]*timeSlotEntry),
+	}
+}
+
+// SetTimeSlotTTL changes how long the scraper caches time-slot tables
+func (s *Scraper) SetTimeSlotTTL(ttl time.Duration) {
+	s.timeSlots = newTimeSlotCache(ttl)
+}
+
+// Waktu returns the cached time-slot table for /kuliahUjian/{tabel},
+// fetching it on first use and refreshing it in the background once it is
+// older than the TTL
+func (s *Scraper) Waktu(ctx context.Context, tabel int) ([]models.WaktuSlot, error) {
+	cache := s.timeSlots
+
+	cache.mutex.Lock()
+	if entry, ok := cache.entries[tabel]; ok {
+		slots := entry.slots
+		if entry.needsRefresh(cache) {
+			entry.refreshing = true
+			entry.lastAttempt = time.Now()
+			go s.refreshWaktu(cache, tabel)
+		}
+		cache.mutex.Unlock()
+		return slots, nil
+	}
+	cache.mutex.Unlock()
+
+	slots, err := s.GetWaktu(ctx, tabel)
+	if err != nil {
+		return nil, err
+	}
+	cache.store(tabel, slots)
+	return slots, nil
+}
+
+// needsRefresh reports whether a stale entry should start a background
+// refresh; the cache mutex must be held
+func (entry *timeSlotEntry) needsRefresh(cache *timeSlotCache) bool {
+	return !entry.refreshing &&
+		time.Since(entry.fetchedAt) > cache.ttl &&
+		time.Since(entry.lastAttempt) > cache.retryInterval
+}
+
+func (s *Scraper) refreshWaktu(cache *timeSlotCache, tabel int) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeSlotRefreshTimeout)
+	defer cancel()
+
+	slots, err := s.GetWaktu(ctx, tabel)
+
+	cache.mutex.Lock()
+	if entry, ok := cache.entries[tabel]; ok {
+		entry.refreshing = false
+	}
+	cache.mutex.Unlock()
+
+	if err != nil {
+		log.Printf("Failed to refresh time-slot table %d, serving stale data: %v", tabel, err)
+		return
+	}
+	cache.store(tabel, slots)
+}
+
+// store keeps a freshly fetched table; empty tables are never cached so a
+// broken page cannot replace good data
+func (cache *timeSlotCache) store(tabel int, slots []models.WaktuSlot) {
+	if len(slots) == 0 {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.entries[tabel] = &timeSlotEntry{
+		slots:     slots,
+		fetchedAt: time.Now(),
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yafyx/baak-api/models"
+)
+
+// failingFetcher counts fetches and fails every one of them
+type failingFetcher struct {
+	fetches atomic.Int32
+}
+
+func (f *failingFetcher) Fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	f.fetches.Add(1)
+	return nil, errors.New("upstream down")
+}
+
+func TestWaktuWaitsAfterFailedRefresh(t *testing.T) {
+	fetcher := &failingFetcher{}
+	s := NewScraper(fetcher)
+	cache := s.timeSlots
+	stale := []models.WaktuSlot{{Periode: 1, Mulai: "07:30", Selesai: "08:30"}}
+	cache.entries[KuliahWaktuTable] = &timeSlotEntry{
+		slots:     stale,
+		fetchedAt: time.Now().Add(-2 * cache.ttl),
+	}
+
+	waitRefresh := func() {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			cache.mutex.Lock()
+			refreshing := cache.entries[KuliahWaktuTable].refreshing
+			cache.mutex.Unlock()
+			if !refreshing {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatal("background refresh did not finish")
+	}
+
+	for i := 0; i < 3; i++ {
+		slots, err := s.Waktu(context.Background(), KuliahWaktuTable)
+		if err != nil || len(slots) != 1 {
+			t.Fatalf("expected stale slots, got %v, %v", slots, err)
+		}
+		waitRefresh()
+	}
+	if got := fetcher.fetches.Load(); got != 1 {
+		t.Fatalf("refresh attempted %d times within the retry interval, want 1", got)
+	}
+
+	// Once the retry interval has passed, the next request tries again
+	cache.mutex.Lock()
+	cache.entries[KuliahWaktuTable].lastAttempt = time.Now().Add(-2 * cache.retryInterval)
+	cache.mutex.Unlock()
+	s.Waktu(context.Background(), KuliahWaktuTable)
+	waitRefresh()
+	if got := fetcher.fetches.Load(); got != 2 {
+		t.Errorf("refresh attempted %d times after the retry interval, want 2", got)
+	}
+}
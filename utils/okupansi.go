@@ -0,0 +1,312 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+// DefaultOccupancyTTL is how long a room occupancy index is served before it
+// is rebuilt in the background
+const DefaultOccupancyTTL = 24 * time.Hour
+
+// occupancyRefreshTimeout bounds a build of the index, which runs apart
+// from the request that asked for it
+const occupancyRefreshTimeout = 10 * time.Minute
+
+// occupancyWait is how long a request waits for the first build of the
+// index before giving up with ErrOccupancyBuilding
+const occupancyWait = 20 * time.Second
+
+// OccupancyRetryAfter is the Retry-After hint sent while the index is built
+const OccupancyRetryAfter = 30 * time.Second
+
+var (
+	// ErrOccupancyNotConfigured is returned when no crawl queries are set
+	ErrOccupancyNotConfigured = errors.New("room occupancy index has no crawl queries")
+	// ErrOccupancyBuilding is returned when the index is not ready yet
+	ErrOccupancyBuilding = errors.New("room occupancy index is still being built")
+)
+
+// occupancyIndex holds the weekly schedule of every room seen while crawling
+// cariJadKul for the configured queries. Builds run in the background, one
+// at a time; like the time-slot cache, a stale index keeps being served
+// while it is rebuilt.
+type occupancyIndex struct {
+	mutex      sync.Mutex
+	ttl        time.Duration
+	queries    []string
+	rooms      map[string]*models.Jadwal
+	builtAt    time.Time
+	refreshing bool
+	// built is closed when the running build finishes
+	built chan struct{}
+	// err is the outcome of the last build that stored nothing
+	err error
+}
+
+func newOccupancyIndex(queries []string, ttl time.Duration) *occupancyIndex {
+	if ttl <= 0 {
+		ttl = DefaultOccupancyTTL
+	}
+	return &occupancyIndex{ttl: ttl, queries: queries}
+}
+
+// SetOccupancy sets the cariJadKul searches, such as class prefixes, that
+// are crawled to build the room occupancy index and how long it is kept
+func (s *Scraper) SetOccupancy(queries []string, ttl time.Duration) {
+	s.occupancy = newOccupancyIndex(queries, ttl)
+}
+
+// occupancyRooms returns the room index. The first call starts a build and
+// waits a while for it; the index is rebuilt in the background once it is
+// older than the TTL.
+func (s *Scraper) occupancyRooms(ctx context.Context) (map[string]*models.Jadwal, error) {
+	index := s.occupancy
+	if len(index.queries) == 0 {
+		return nil, ErrOccupancyNotConfigured
+	}
+
+	index.mutex.Lock()
+	if index.rooms != nil {
+		rooms := index.rooms
+		if time.Since(index.builtAt) > index.ttl {
+			s.startOccupancyBuild(index)
+		}
+		index.mutex.Unlock()
+		return rooms, nil
+	}
+	built := s.startOccupancyBuild(index)
+	index.mutex.Unlock()
+
+	timer := time.NewTimer(occupancyWait)
+	defer timer.Stop()
+	select {
+	case <-built:
+	case <-timer.C:
+		return nil, ErrOccupancyBuilding
+	case <-ctx.Done():
+		return nil, ErrOccupancyBuilding
+	}
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	if index.rooms == nil {
+		return nil, index.err
+	}
+	return index.rooms, nil
+}
+
+// startOccupancyBuild starts a background build unless one is running and
+// returns the channel closed when it finishes. index.mutex must be held.
+func (s *Scraper) startOccupancyBuild(index *occupancyIndex) <-chan struct{} {
+	if !index.refreshing {
+		index.refreshing = true
+		index.built = make(chan struct{})
+		go s.refreshOccupancy(index)
+	}
+	return index.built
+}
+
+func (s *Scraper) refreshOccupancy(index *occupancyIndex) {
+	ctx, cancel := context.WithTimeout(context.Background(), occupancyRefreshTimeout)
+	defer cancel()
+
+	rooms, err := s.buildOccupancy(ctx, index.queries)
+	if err != nil {
+		log.Printf("Room occupancy index build stopped early with %d rooms: %v", len(rooms), err)
+	}
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	// An empty index never replaces good data
+	if len(rooms) > 0 {
+		index.rooms = rooms
+		index.builtAt = time.Now()
+		if err != nil {
+			// Serve what was crawled, but rebuild it on the next request
+			index.builtAt = time.Time{}
+		}
+		index.err = nil
+	} else if index.rooms == nil {
+		if err == nil {
+			err = fmt.Errorf("room occupancy crawl found no rooms")
+		}
+		index.err = err
+	}
+	index.refreshing = false
+	close(index.built)
+}
+
+// buildOccupancy crawls every query and files each session under its room.
+// Sessions found by more than one query are kept once. Queries that fail are
+// logged and skipped; when the context ends, the rooms crawled so far are
+// returned along with the error.
+func (s *Scraper) buildOccupancy(ctx context.Context, queries []string) (map[string]*models.Jadwal, error) {
+	rooms := make(map[string]*models.Jadwal)
+	seen := make(map[string]bool)
+	failed := 0
+	var lastErr error
+
+	for _, query := range queries {
+		if ctx.Err() != nil {
+			return rooms, canceledError(ctx)
+		}
+
+		jadwal, err := s.SearchJadwal(ctx, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return rooms, err
+			}
+			log.Printf("Failed to crawl jadwal %q for the room occupancy index: %v", query, err)
+			failed++
+			lastErr = err
+			continue
+		}
+
+		for _, hari := range jadwalHari {
+			for _, mataKuliah := range *jadwalDays(&jadwal)[hari] {
+				kode := normalizeRuangKode(mataKuliah.Ruang)
+				if kode == "" {
+					continue
+				}
+				key := strings.Join([]string{kode, hari, mataKuliah.Kelas, mataKuliah.Nama, mataKuliah.Waktu}, "|")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				if rooms[kode] == nil {
+					rooms[kode] = &models.Jadwal{}
+				}
+				addToJadwal(rooms[kode], hari, mataKuliah)
+			}
+		}
+	}
+
+	if failed == len(queries) {
+		return rooms, fmt.Errorf("all %d room occupancy queries failed: %w", failed, lastErr)
+	}
+	return rooms, nil
+}
+
+// GetRuangJadwal returns the weekly schedule of a room from the occupancy
+// index. Rooms that never appear in the crawl get an empty week.
+func (s *Scraper) GetRuangJadwal(ctx context.Context, kode string) (models.Jadwal, error) {
+	rooms, err := s.occupancyRooms(ctx)
+	if err != nil {
+		return models.Jadwal{}, err
+	}
+
+	jadwal := models.Jadwal{}
+	if room, ok := rooms[normalizeRuangKode(kode)]; ok {
+		jadwal = *room
+	}
+	for _, sessions := range jadwalDays(&jadwal) {
+		sorted := append([]models.MataKuliah(nil), *sessions...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Mulai < sorted[j].Mulai
+		})
+		*sessions = sorted
+	}
+	return jadwal, nil
+}
+
+// GetRuangKosong returns the rooms of the occupancy index that have no
+// session on hari during periode, sorted by code. When gedung is set only
+// rooms in that building are returned. A day without lectures or a period
+// missing from the kuliahUjian table is reported as a RuangQueryError.
+func (s *Scraper) GetRuangKosong(ctx context.Context, hari string, periode int, gedung string) ([]models.Ruang, error) {
+	if hari = NormalizeHari(hari); hari == "" {
+		return nil, &RuangQueryError{Message: "Hari must be a day from Senin to Sabtu"}
+	}
+
+	slots, err := s.Waktu(ctx, KuliahWaktuTable)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := findWaktuSlot(slots, periode); !ok {
+		return nil, &RuangQueryError{Message: fmt.Sprintf("Jam %d is not a lecture period", periode)}
+	}
+
+	rooms, err := s.occupancyRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gedung = strings.ToUpper(strings.TrimSpace(gedung))
+	kosong := []models.Ruang{}
+	for kode, jadwal := range rooms {
+		if isOccupied(*jadwalDays(jadwal)[hari], periode) {
+			continue
+		}
+
+		ruang, err := s.ParseRuang(kode)
+		if err != nil {
+			// Rooms such as "AULA" have no building to filter on
+			if gedung != "" {
+				continue
+			}
+			ruang = models.Ruang{Kode: kode}
+		}
+		if gedung != "" && ruang.Gedung != gedung {
+			continue
+		}
+		kosong = append(kosong, ruang)
+	}
+
+	sort.Slice(kosong, func(i, j int) bool {
+		return kosong[i].Kode < kosong[j].Kode
+	})
+	return kosong, nil
+}
+
+func isOccupied(sessions []models.MataKuliah, periode int) bool {
+	for _, mataKuliah := range sessions {
+		for _, p := range mataKuliah.Periode {
+			if p == periode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PeriodeAt returns the lecture period running at a clock time such as
+// "09:45", using the kuliahUjian time-slot table
+func (s *Scraper) PeriodeAt(ctx context.Context, clock string) (models.WaktuSlot, bool, error) {
+	minutes, ok := clockMinutes(strings.ReplaceAll(clock, ".", ":"))
+	if !ok {
+		return models.WaktuSlot{}, false, nil
+	}
+
+	slots, err := s.Waktu(ctx, KuliahWaktuTable)
+	if err != nil {
+		return models.WaktuSlot{}, false, err
+	}
+	for _, slot := range slots {
+		mulai, okMulai := clockMinutes(slot.Mulai)
+		selesai, okSelesai := clockMinutes(slot.Selesai)
+		if okMulai && okSelesai && minutes >= mulai && minutes < selesai {
+			return slot, true, nil
+		}
+	}
+	return models.WaktuSlot{}, false, nil
+}
+
+// NormalizeHari returns a day name as the jadwal pages spell it, or "" when
+// it is not a day with lectures
+func NormalizeHari(hari string) string {
+	hari = normalizeHari(strings.TrimSpace(hari))
+	if !isHari(hari) {
+		return ""
+	}
+	return hari
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRuangOccupancy(t *testing.T) {
+	s := newFixtureScraper()
+	s.SetOccupancy([]string{"2IA01", "2IA0"}, 0)
+	ctx := context.Background()
+
+	jadwal, err := s.GetRuangJadwal(ctx, "d462")
+	if err != nil {
+		t.Fatalf("GetRuangJadwal failed: %v", err)
+	}
+	if len(jadwal.Senin) != 3 || jadwal.Senin[0].Kelas != "2IA01" || jadwal.Senin[0].Mulai != "07:30" {
+		t.Fatalf("unexpected D462 schedule: %+v", jadwal.Senin)
+	}
+	for _, kode := range []string{"D4.62", "D-462"} {
+		if other, err := s.GetRuangJadwal(ctx, kode); err != nil || len(other.Senin) != len(jadwal.Senin) {
+			t.Errorf("GetRuangJadwal(%q) does not match D462: %+v, %v", kode, other.Senin, err)
+		}
+	}
+
+	kosong, err := s.GetRuangKosong(ctx, "Senin", 1, "D")
+	if err != nil {
+		t.Fatalf("GetRuangKosong failed: %v", err)
+	}
+	for _, ruang := range kosong {
+		if ruang.Kode == "D462" {
+			t.Errorf("D462 is in use on Senin period 1 but was listed as free")
+		}
+		if ruang.Gedung != "D" {
+			t.Errorf("room %s is outside building D", ruang.Kode)
+		}
+	}
+	if len(kosong) == 0 {
+		t.Errorf("expected free rooms in building D")
+	}
+
+	for _, periode := range []int{0, 99} {
+		var queryErr *RuangQueryError
+		if _, err := s.GetRuangKosong(ctx, "Senin", periode, ""); !errors.As(err, &queryErr) {
+			t.Errorf("period %d should be rejected, got %v", periode, err)
+		}
+	}
+	for _, hari := range []string{"Minggu", "Besok", ""} {
+		var queryErr *RuangQueryError
+		if _, err := s.GetRuangKosong(ctx, hari, 1, ""); !errors.As(err, &queryErr) {
+			t.Errorf("hari %q should be rejected, got %v", hari, err)
+		}
+	}
+	if _, err := s.GetRuangKosong(ctx, "jumat", 1, ""); err != nil {
+		t.Errorf("lowercase hari without apostrophe should be accepted, got %v", err)
+	}
+
+	slot, ok, err := s.PeriodeAt(ctx, "09.45")
+	if err != nil || !ok || slot.Periode != 3 {
+		t.Errorf("PeriodeAt(09.45) = %+v, %v, %v", slot, ok, err)
+	}
+}
+
+func TestRuangOccupancyNotConfigured(t *testing.T) {
+	_, err := newFixtureScraper().GetRuangKosong(context.Background(), "Senin", 1, "")
+	if !errors.Is(err, ErrOccupancyNotConfigured) {
+		t.Fatalf("expected ErrOccupancyNotConfigured, got %v", err)
+	}
+}
+
+func TestRuangOccupancySkipsFailedQueries(t *testing.T) {
+	s := newFixtureScraper()
+	// There is no fixture for the second query
+	s.SetOccupancy([]string{"2IA0", "9ZZ99"}, 0)
+
+	jadwal, err := s.GetRuangJadwal(context.Background(), "E532")
+	if err != nil {
+		t.Fatalf("GetRuangJadwal failed: %v", err)
+	}
+	if len(jadwal.Jumat) != 1 {
+		t.Fatalf("unexpected E532 schedule: %+v", jadwal)
+	}
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden files in testdata/golden")
+
+func newFixtureScraper() *Scraper {
+	return NewScraper(NewFileFetcher("testdata"))
+}
+
+func TestParsersGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(ctx context.Context, s *Scraper) (interface{}, error)
+	}{
+		{
+			name: "jadwal",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetJadwal(ctx, BaseURL+"/jadwal/cariJadKul?_token=abc&teks=2IA01")
+			},
+		},
+		{
+			name: "jadwal_kosong",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetJadwal(ctx, BaseURL+"/jadwal/cariJadKul?_token=abc&teks=9ZZ99")
+			},
+		},
+		{
+			name: "jadwal_kelas",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetJadwalPerKelas(ctx, BaseURL+"/jadwal/cariJadKul?_token=abc&teks=2IA0")
+			},
+		},
+		{
+			name: "dosen_jadwal",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				jadwal, err := s.GetJadwal(ctx, BaseURL+"/jadwal/cariJadKul?_token=abc&teks=2IA0")
+				return FilterJadwalDosen(jadwal, "ahmad  fauzi"), err
+			},
+		},
+		{
+			name: "uts",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetUTS(ctx, BaseURL+"/jadwal/cariUts?&teks=2IA01")
+			},
+		},
+		{
+			name: "uas",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetUAS(ctx, BaseURL+"/jadwal/cariUas?_token=abc&teks=2IA01")
+			},
+		},
+		{
+			name: "uu",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetUU(ctx, BaseURL+"/jadwal/cariUu?_token=abc&teks=4IA01")
+			},
+		},
+		{
+			name: "kelasbaru",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetKelasbaru(ctx, BaseURL+"/cariKelasBaru?_token=abc&tipeKelasBaru=Kelas&teks=3IA01")
+			},
+		},
+		{
+			name: "mahasiswabaru",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetMahasiswaBaru(ctx, BaseURL+"/cariMhsBaru?_token=abc&tipeMhsBaru=Kelas&teks=1IA01")
+			},
+		},
+		{
+			name: "kegiatan",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetKegiatan(ctx, BaseURL)
+			},
+		},
+		{
+			name: "kegiatan_tree",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetKegiatanTree(ctx, BaseURL)
+			},
+		},
+		{
+			name: "waktu",
+			parse: func(ctx context.Context, s *Scraper) (interface{}, error) {
+				return s.GetWaktu(ctx, KuliahWaktuTable)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(context.Background(), newFixtureScraper())
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+			compareGolden(t, tt.name, got)
+		})
+	}
+}
+
+func TestGetCSRFToken(t *testing.T) {
+	token, err := newFixtureScraper().GetCSRFToken(context.Background(), BaseURL+"/jadwal")
+	if err != nil {
+		t.Fatalf("GetCSRFToken failed: %v", err)
+	}
+	if token != "fixturetoken0123456789abcdefghijklmnopqrstu" {
+		t.Errorf("unexpected token %q", token)
+	}
+}
+
+func compareGolden(t *testing.T, name string, got interface{}) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	actual = append(actual, '\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *update {
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("result does not match %s\n got: %s\nwant: %s", path, actual, expected)
+	}
+}
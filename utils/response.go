@@ -2,10 +2,15 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strings"
+	"strconv"
 )
 
+// StatusClientClosedRequest is reported when the client disconnects before
+// the upstream fetch finishes
+const StatusClientClosedRequest = 499
+
 type Response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
@@ -41,26 +46,66 @@ func WriteInternalServerError(w http.ResponseWriter) {
 	WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
 }
 
+// WriteHTTPError maps an upstream fetch or parse error to a response with
+// the matching status code
 func WriteHTTPError(w http.ResponseWriter, err error) {
-	message := err.Error()
+	var challengeErr *ChallengeError
+	var timeoutErr *TimeoutError
+	var unreachableErr *UnreachableError
+	var statusErr *UpstreamStatusError
+	var csrfErr *CSRFTokenMissingError
+	var parseErr *ParseError
+	var ruangQueryErr *RuangQueryError
 
-	// Handle specific HTTP errors with appropriate status codes
-	if strings.Contains(message, "unexpected status code: 403") ||
-		strings.Contains(message, "access forbidden (403)") {
+	switch {
+	case errors.As(err, &challengeErr):
+		if challengeErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(challengeErr.RetryAfter.Seconds())))
+		}
+		WriteErrorResponse(w, http.StatusServiceUnavailable,
+			"BAAK is currently blocking automated access. Please try again later.")
+	case errors.As(err, &timeoutErr):
+		WriteErrorResponse(w, http.StatusGatewayTimeout,
+			"The backend server did not respond in time. Please try again later.")
+	case errors.Is(err, ErrRequestCanceled):
+		WriteErrorResponse(w, StatusClientClosedRequest, "Request canceled")
+	case errors.As(err, &unreachableErr):
+		WriteErrorResponse(w, http.StatusBadGateway,
+			"The backend server could not be reached. Please try again later.")
+	case errors.As(err, &statusErr):
+		writeUpstreamStatusError(w, statusErr.StatusCode)
+	case errors.As(err, &csrfErr):
+		WriteErrorResponse(w, http.StatusBadGateway,
+			"The backend server did not provide a session token. Please try again later.")
+	case errors.As(err, &parseErr):
+		WriteErrorResponse(w, http.StatusBadGateway,
+			"The backend server returned a page that could not be read.")
+	case errors.As(err, &ruangQueryErr):
+		WriteValidationError(w, ruangQueryErr.Message)
+	case errors.Is(err, ErrOccupancyBuilding):
+		w.Header().Set("Retry-After", strconv.Itoa(int(OccupancyRetryAfter.Seconds())))
+		WriteErrorResponse(w, http.StatusServiceUnavailable,
+			"Room occupancy is still being collected. Please try again later.")
+	case errors.Is(err, ErrOccupancyNotConfigured):
+		WriteErrorResponse(w, http.StatusServiceUnavailable,
+			"Room occupancy is not available because no crawl queries are configured.")
+	default:
+		// Default to internal server error for other cases
+		WriteInternalServerError(w)
+	}
+}
+
+func writeUpstreamStatusError(w http.ResponseWriter, statusCode int) {
+	switch {
+	case statusCode == http.StatusForbidden:
 		WriteErrorResponse(w, http.StatusServiceUnavailable,
 			"Service temporarily unavailable due to access restrictions. Please try again later.")
-		return
-	} else if strings.Contains(message, "unexpected status code: 429") {
+	case statusCode == http.StatusTooManyRequests:
 		WriteErrorResponse(w, http.StatusTooManyRequests,
 			"Too many requests to the backend server. Please try again later.")
-		return
-	} else if strings.Contains(message, "unexpected status code: 5") {
-		// Handle any 5xx error
+	default:
+		// Handle any 5xx or otherwise unexpected status
 		WriteErrorResponse(w, http.StatusBadGateway,
 			"The backend server is experiencing issues. Please try again later.")
-		return
 	}
-
-	// Default to internal server error for other cases
-	WriteInternalServerError(w)
 }
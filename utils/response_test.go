@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWriteHTTPError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		retryAfter string
+	}{
+		{"challenge", &ChallengeError{URL: BaseURL, RetryAfter: 90 * time.Second}, http.StatusServiceUnavailable, "90"},
+		{"timeout", &TimeoutError{URL: BaseURL, Err: context.DeadlineExceeded}, http.StatusGatewayTimeout, ""},
+		{"budget exhausted", budgetExhaustedError(2, nil), http.StatusGatewayTimeout, ""},
+		{"canceled", fmt.Errorf("%w: %w", ErrRequestCanceled, context.Canceled), StatusClientClosedRequest, ""},
+		{"unreachable", &UnreachableError{URL: BaseURL, Err: errors.New("connection refused")}, http.StatusBadGateway, ""},
+		{"status 403", &UpstreamStatusError{URL: BaseURL, StatusCode: http.StatusForbidden}, http.StatusServiceUnavailable, ""},
+		{"status 429", &UpstreamStatusError{URL: BaseURL, StatusCode: http.StatusTooManyRequests}, http.StatusTooManyRequests, ""},
+		{"status 500", &UpstreamStatusError{URL: BaseURL, StatusCode: http.StatusInternalServerError}, http.StatusBadGateway, ""},
+		{"csrf", fmt.Errorf("wrapped: %w", &CSRFTokenMissingError{URL: BaseURL}), http.StatusBadGateway, ""},
+		{"parse", &ParseError{URL: BaseURL, Err: errors.New("bad html")}, http.StatusBadGateway, ""},
+		{"ruang query", &RuangQueryError{Message: "bad jam"}, http.StatusBadRequest, ""},
+		{"occupancy building", ErrOccupancyBuilding, http.StatusServiceUnavailable, "30"},
+		{"occupancy not configured", ErrOccupancyNotConfigured, http.StatusServiceUnavailable, ""},
+		{"other", errors.New("boom"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			WriteHTTPError(recorder, tt.err)
+			if recorder.Code != tt.status {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.status)
+			}
+			if got := recorder.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+		})
+	}
+}
+
+func TestFetchDocumentWithRetryUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	_, err := FetchDocumentWithRetry(context.Background(), url, "", 1)
+	var unreachableErr *UnreachableError
+	if !errors.As(err, &unreachableErr) {
+		t.Fatalf("expected UnreachableError, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+// Gedung is a registry entry describing a building, keyed by the code
+// rooms start with ("D", "J1")
+type Gedung struct {
+	Kode   string `json:"kode"`
+	Kampus string `json:"kampus"`
+	Alamat string `json:"alamat"`
+}
+
+// ruangCode matches room codes such as "D462" or "J1413"
+var ruangCode = regexp.MustCompile(`^([A-Z]+)(\d{3,})$`)
+
+// LoadGedungRegistry reads buildings from a JSON file holding a list of
+// {"kode": ..., "kampus": ..., "alamat": ...} objects
+func LoadGedungRegistry(path string) ([]Gedung, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gedung registry: %v", err)
+	}
+
+	var registry []Gedung
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("failed to parse gedung registry: %v", err)
+	}
+	return registry, nil
+}
+
+// SetGedungRegistry replaces the buildings the scraper enriches rooms with
+func (s *Scraper) SetGedungRegistry(registry []Gedung) {
+	gedung := make(map[string]Gedung, len(registry))
+	for _, entry := range registry {
+		entry.Kode = strings.ToUpper(strings.TrimSpace(entry.Kode))
+		if entry.Kode != "" {
+			gedung[entry.Kode] = entry
+		}
+	}
+	s.gedung = gedung
+}
+
+// ParseRuang splits a room code into building, floor and room number. The
+// last three digits are the floor and the room on it, so "D462" is room 62
+// on floor 4 of building D, and any digits before them belong to the
+// building, as in "J1413" for building J1. Buildings in the registry add
+// their campus and address.
+func (s *Scraper) ParseRuang(kode string) (models.Ruang, error) {
+	normalized := normalizeRuangKode(kode)
+	m := ruangCode.FindStringSubmatch(normalized)
+	if m == nil {
+		return models.Ruang{}, fmt.Errorf("unrecognized room code %q", kode)
+	}
+
+	digits := m[2]
+	split := len(digits) - 3
+	lantai, _ := strconv.Atoi(digits[split : split+1])
+	ruang := models.Ruang{
+		Kode:   normalized,
+		Gedung: m[1] + digits[:split],
+		Lantai: lantai,
+		Nomor:  digits[split+1:],
+	}
+
+	if gedung, ok := s.gedung[ruang.Gedung]; ok {
+		ruang.Kampus = gedung.Kampus
+		ruang.Alamat = gedung.Alamat
+	}
+	return ruang, nil
+}
+
+// normalizeRuangKode is the single form room codes are parsed, indexed and
+// looked up under: upper case without spaces, dashes or dots, so "d4.62"
+// and "D 462" both become "D462"
+func normalizeRuangKode(kode string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' || r == '.' {
+			return -1
+		}
+		return r
+	}, kode))
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+func TestParseRuang(t *testing.T) {
+	s := newFixtureScraper()
+	s.SetGedungRegistry([]Gedung{{Kode: "d", Kampus: "Kampus D", Alamat: "Jl. Margonda Raya No. 100, Depok"}})
+
+	tests := map[string]models.Ruang{
+		"D462":  {Kode: "D462", Gedung: "D", Lantai: 4, Nomor: "62", Kampus: "Kampus D", Alamat: "Jl. Margonda Raya No. 100, Depok"},
+		"e 531": {Kode: "E531", Gedung: "E", Lantai: 5, Nomor: "31"},
+		"J1413": {Kode: "J1413", Gedung: "J1", Lantai: 4, Nomor: "13"},
+	}
+	for kode, want := range tests {
+		got, err := s.ParseRuang(kode)
+		if err != nil {
+			t.Errorf("ParseRuang(%q) failed: %v", kode, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseRuang(%q) = %+v, want %+v", kode, got, want)
+		}
+	}
+
+	for _, kode := range []string{"", "AULA", "D46", "462"} {
+		if _, err := s.ParseRuang(kode); err == nil {
+			t.Errorf("ParseRuang(%q) should fail", kode)
+		}
+	}
+}
+
+func TestNormalizeRuangKode(t *testing.T) {
+	for _, kode := range []string{"D462", "d462", "D 462", "D4.62", "D-462", " d4-6.2 "} {
+		if got := normalizeRuangKode(kode); got != "D462" {
+			t.Errorf("normalizeRuangKode(%q) = %q, want D462", kode, got)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yafyx/baak-api/models"
+)
+
+// Scraper runs the BAAK page parsers against a Fetcher backend
+type Scraper struct {
+	fetcher   Fetcher
+	tokens    *TokenManager
+	timeSlots *timeSlotCache
+	kategori  *kategoriMatcher
+	gedung    map[string]Gedung
+	occupancy *occupancyIndex
+}
+
+// NewScraper returns a scraper that loads pages through fetcher
+func NewScraper(fetcher Fetcher) *Scraper {
+	return &Scraper{
+		fetcher:   fetcher,
+		tokens:    NewTokenManager(DefaultTokenTTL),
+		timeSlots: newTimeSlotCache(DefaultTimeSlotTTL),
+		kategori:  newKategoriMatcher(DefaultKategoriRules),
+		occupancy: newOccupancyIndex(nil, DefaultOccupancyTTL),
+	}
+}
+
+// SetTokenTTL changes how long the scraper reuses a CSRF token
+func (s *Scraper) SetTokenTTL(ttl time.Duration) {
+	s.tokens = NewTokenManager(ttl)
+}
+
+var defaultScraper = NewScraper(HTTPFetcher{})
+
+// DefaultScraper returns the scraper used by the package-level helpers
+func DefaultScraper() *Scraper {
+	return defaultScraper
+}
+
+// SetDefaultScraper replaces the scraper used by the package-level helpers.
+// It is meant to be called once during startup.
+func SetDefaultScraper(s *Scraper) {
+	defaultScraper = s
+}
+
+func (s *Scraper) fetch(ctx context.Context, url string) (*goquery.Document, error) {
+	return s.fetcher.Fetch(ctx, url)
+}
+
+// GetCSRFToken fetches a page and extracts the CSRF token using the default scraper
+func GetCSRFToken(ctx context.Context, url string) (string, error) {
+	return defaultScraper.GetCSRFToken(ctx, url)
+}
+
+// WithCSRFToken runs search with a cached CSRF token using the default scraper
+func WithCSRFToken(ctx context.Context, pageURLs []string, search func(token string) error) error {
+	return defaultScraper.WithCSRFToken(ctx, pageURLs, search)
+}
+
+func GetJadwal(ctx context.Context, url string) (models.Jadwal, error) {
+	return defaultScraper.GetJadwal(ctx, url)
+}
+
+// GetJadwalPerKelas returns a search result grouped by kelas using the default scraper
+func GetJadwalPerKelas(ctx context.Context, url string) ([]models.JadwalKelas, error) {
+	return defaultScraper.GetJadwalPerKelas(ctx, url)
+}
+
+// Waktu returns the cached period→time table using the default scraper
+func Waktu(ctx context.Context, tabel int) ([]models.WaktuSlot, error) {
+	return defaultScraper.Waktu(ctx, tabel)
+}
+
+func GetKegiatan(ctx context.Context, url string) ([]models.Kegiatan, error) {
+	return defaultScraper.GetKegiatan(ctx, url)
+}
+
+func GetKegiatanTree(ctx context.Context, url string) ([]models.KegiatanNode, error) {
+	return defaultScraper.GetKegiatanTree(ctx, url)
+}
+
+func GetKelasbaru(ctx context.Context, baseURL string) ([]models.KelasBaru, error) {
+	return defaultScraper.GetKelasbaru(ctx, baseURL)
+}
+
+func GetMahasiswaBaru(ctx context.Context, url string) ([]models.MahasiswaBaru, error) {
+	return defaultScraper.GetMahasiswaBaru(ctx, url)
+}
+
+func GetUTS(ctx context.Context, url string) ([]models.UTS, error) {
+	return defaultScraper.GetUTS(ctx, url)
+}
+
+func GetUAS(ctx context.Context, url string) ([]models.UAS, error) {
+	return defaultScraper.GetUAS(ctx, url)
+}
+
+func GetUU(ctx context.Context, url string) ([]models.UU, error) {
+	return defaultScraper.GetUU(ctx, url)
+}
+
+// WithJadwalSearch runs fetch on a cariJadKul search URL using the default scraper
+func WithJadwalSearch(ctx context.Context, search string, fetch func(searchURL string) error) error {
+	return defaultScraper.WithJadwalSearch(ctx, search, fetch)
+}
+
+// SearchJadwal runs a cariJadKul search using the default scraper
+func SearchJadwal(ctx context.Context, search string) (models.Jadwal, error) {
+	return defaultScraper.SearchJadwal(ctx, search)
+}
+
+func ParseRuang(kode string) (models.Ruang, error) {
+	return defaultScraper.ParseRuang(kode)
+}
+
+func GetRuangJadwal(ctx context.Context, kode string) (models.Jadwal, error) {
+	return defaultScraper.GetRuangJadwal(ctx, kode)
+}
+
+func GetRuangKosong(ctx context.Context, hari string, periode int, gedung string) ([]models.Ruang, error) {
+	return defaultScraper.GetRuangKosong(ctx, hari, periode, gedung)
+}
+
+func PeriodeAt(ctx context.Context, clock string) (models.WaktuSlot, bool, error) {
+	return defaultScraper.PeriodeAt(ctx, clock)
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Column describes a table column by its header name and the alternative
+// header texts BAAK has used for it
+type Column struct {
+	Name    string
+	Aliases []string
+	// Optional columns are not reported as missing and are not read by
+	// position
+	Optional bool
+}
+
+// TableRow is a data row keyed by Column.Name
+type TableRow map[string]string
+
+// Get returns the trimmed cell text for a column, or "" when absent
+func (r TableRow) Get(name string) string {
+	return r[name]
+}
+
+// Table is the result of reading an HTML table through its header row
+type Table struct {
+	Rows []TableRow
+	// HeaderFound is false when no header row matched and the columns were
+	// read by position instead
+	HeaderFound bool
+	// Missing lists expected columns that are not in the header
+	Missing []string
+	// Unexpected lists header texts that do not match any column
+	Unexpected []string
+}
+
+// ExtractTable reads the rows of table, mapping cells to columns by the text
+// of its header row. When no header row can be recognized the columns are
+// read by position in the order they are given.
+func ExtractTable(table *goquery.Selection, columns []Column) Table {
+	result := Table{}
+	rows := table.Find("tr")
+
+	headerIndex := -1
+	var headers []string
+	rows.EachWithBreak(func(i int, row *goquery.Selection) bool {
+		cells := row.Find("th")
+		if cells.Length() == 0 {
+			cells = row.Find("td")
+			// A header written with td cells must name at least two columns
+			if countMatchingHeaders(cells, columns) < 2 {
+				return true
+			}
+		}
+		headerIndex = i
+		cells.Each(func(_ int, cell *goquery.Selection) {
+			headers = append(headers, strings.TrimSpace(cell.Text()))
+		})
+		return false
+	})
+
+	// Map cell positions to column names
+	positions := make(map[int]string)
+	if headerIndex >= 0 {
+		result.HeaderFound = true
+		found := make(map[string]bool)
+		for i, header := range headers {
+			if column, ok := matchColumn(header, columns); ok {
+				positions[i] = column.Name
+				found[column.Name] = true
+			} else if header != "" {
+				result.Unexpected = append(result.Unexpected, header)
+			}
+		}
+		for _, column := range columns {
+			if !found[column.Name] && !column.Optional {
+				result.Missing = append(result.Missing, column.Name)
+			}
+		}
+	} else {
+		for _, column := range columns {
+			if !column.Optional {
+				positions[len(positions)] = column.Name
+			}
+		}
+	}
+
+	width := len(positions)
+	if headerIndex >= 0 {
+		width = len(headers)
+	}
+
+	rows.Each(func(i int, row *goquery.Selection) {
+		if i <= headerIndex {
+			return
+		}
+
+		cells := row.Find("td")
+		// Skip spacer, message and other rows that do not span the table
+		if cells.Length() < width {
+			return
+		}
+
+		tableRow := make(TableRow, len(positions))
+		for index, name := range positions {
+			tableRow[name] = strings.TrimSpace(cells.Eq(index).Text())
+		}
+		result.Rows = append(result.Rows, tableRow)
+	})
+
+	return result
+}
+
+func countMatchingHeaders(cells *goquery.Selection, columns []Column) int {
+	count := 0
+	cells.Each(func(_ int, cell *goquery.Selection) {
+		if _, ok := matchColumn(cell.Text(), columns); ok {
+			count++
+		}
+	})
+	return count
+}
+
+func matchColumn(header string, columns []Column) (Column, bool) {
+	key := normalizeHeader(header)
+	if key == "" {
+		return Column{}, false
+	}
+
+	for _, column := range columns {
+		if normalizeHeader(column.Name) == key {
+			return column, true
+		}
+		for _, alias := range column.Aliases {
+			if normalizeHeader(alias) == key {
+				return column, true
+			}
+		}
+	}
+	return Column{}, false
+}
+
+// normalizeHeader lowercases a header and drops everything but letters and
+// digits, so "Mata Kuliah", "MATA KULIAH" and "Mata-Kuliah" compare equal
+func normalizeHeader(header string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
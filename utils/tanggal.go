@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ISODate is the layout used for dates in API responses
+const ISODate = "2006-01-02"
+
+// bulanIndonesia maps Indonesian month names and the abbreviations BAAK uses
+var bulanIndonesia = map[string]time.Month{
+	"januari": time.January, "jan": time.January,
+	"februari": time.February, "pebruari": time.February, "feb": time.February, "peb": time.February,
+	"maret": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"mei":  time.May,
+	"juni": time.June, "jun": time.June,
+	"juli": time.July, "jul": time.July,
+	"agustus": time.August, "agu": time.August, "agt": time.August, "agus": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"oktober": time.October, "okt": time.October,
+	"november": time.November, "nopember": time.November, "nov": time.November, "nop": time.November,
+	"desember": time.December, "des": time.December,
+}
+
+var (
+	// Separators BAAK uses between the two ends of a range
+	rangeSeparator = regexp.MustCompile(`\s*(?:-|–|—|s\.?/?d\.?|sampai|hingga)\s*`)
+	// Leading day names such as "Senin," are ignored
+	dayNamePrefix = regexp.MustCompile(`^(?:senin|selasa|rabu|kamis|jum'?at|sabtu|minggu)\s*,?\s*`)
+	numericDate   = regexp.MustCompile(`^(\d{1,2})[/.](\d{1,2})[/.](\d{4})$`)
+	textDate      = regexp.MustCompile(`^(\d{1,2})(?:\s+([a-z]+)\.?)?(?:\s+(\d{4}))?$`)
+)
+
+// partialDate is one end of a range, where the month and year may be left
+// out and taken from the other end
+type partialDate struct {
+	day   int
+	month time.Month
+	year  int
+}
+
+func parsePartialDate(text string) (partialDate, error) {
+	text = strings.TrimSpace(dayNamePrefix.ReplaceAllString(strings.TrimSpace(text), ""))
+
+	if m := numericDate.FindStringSubmatch(text); m != nil {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year, _ := strconv.Atoi(m[3])
+		if month < 1 || month > 12 {
+			return partialDate{}, fmt.Errorf("invalid month in %q", text)
+		}
+		return partialDate{day: day, month: time.Month(month), year: year}, nil
+	}
+
+	m := textDate.FindStringSubmatch(text)
+	if m == nil {
+		return partialDate{}, fmt.Errorf("unrecognized date %q", text)
+	}
+
+	date := partialDate{}
+	date.day, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		month, ok := bulanIndonesia[m[2]]
+		if !ok {
+			return partialDate{}, fmt.Errorf("unknown month %q", m[2])
+		}
+		date.month = month
+	}
+	if m[3] != "" {
+		date.year, _ = strconv.Atoi(m[3])
+	}
+	return date, nil
+}
+
+func (d partialDate) toTime() (time.Time, error) {
+	if d.month == 0 || d.year == 0 {
+		return time.Time{}, fmt.Errorf("incomplete date")
+	}
+	t := time.Date(d.year, d.month, d.day, 0, 0, 0, 0, time.UTC)
+	// time.Date normalizes overflow, so a changed day means it did not exist
+	if t.Day() != d.day {
+		return time.Time{}, fmt.Errorf("invalid day %d %s %d", d.day, d.month, d.year)
+	}
+	return t, nil
+}
+
+// ParseTanggal parses a single Indonesian date such as "23 Agustus 2025",
+// "Senin, 28 April 2025" or "30/04/2025"
+func ParseTanggal(text string) (time.Time, error) {
+	date, err := parsePartialDate(strings.ToLower(text))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return date.toTime()
+}
+
+// ParseTanggalRange parses an Indonesian date or date range. It understands
+// the abbreviated forms BAAK uses, where the start borrows the month and year
+// of the end ("1 - 5 Maret 2025", "28 Februari - 3 Maret 2025"), as well as
+// ranges across years ("23 Desember 2024 - 3 Januari 2025").
+func ParseTanggalRange(text string) (start, end time.Time, err error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	if normalized == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("empty date")
+	}
+
+	parts := rangeSeparator.Split(normalized, -1)
+	switch len(parts) {
+	case 1:
+		start, err = ParseTanggal(parts[0])
+		return start, start, err
+	case 2:
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unrecognized date range %q", text)
+	}
+
+	first, err := parsePartialDate(parts[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	last, err := parsePartialDate(parts[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	end, err = last.toTime()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	// Fill in what the start leaves out from the end of the range
+	if first.month == 0 {
+		first.month = last.month
+	}
+	if first.year == 0 {
+		first.year = last.year
+		if first.month > last.month {
+			first.year--
+		}
+	}
+
+	start, err = first.toTime()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("range %q ends before it starts", text)
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,45 @@
+package utils
+
+import "testing"
+
+func TestParseTanggalRange(t *testing.T) {
+	tests := []struct {
+		text  string
+		start string
+		end   string
+	}{
+		{"23 Agustus 2025", "2025-08-23", "2025-08-23"},
+		{"10 - 21 Februari 2025", "2025-02-10", "2025-02-21"},
+		{"1 - 5 Maret 2025", "2025-03-01", "2025-03-05"},
+		{"28 Februari - 3 Maret 2025", "2025-02-28", "2025-03-03"},
+		{"23 Desember 2024 - 3 Januari 2025", "2024-12-23", "2025-01-03"},
+		{"23 Desember - 3 Januari 2025", "2024-12-23", "2025-01-03"},
+		{"1 s/d 5 Mei 2025", "2025-05-01", "2025-05-05"},
+		{"14 – 26 Juli 2025", "2025-07-14", "2025-07-26"},
+		{"2 Sept 2025", "2025-09-02", "2025-09-02"},
+		{"Senin, 28 April 2025", "2025-04-28", "2025-04-28"},
+		{"30/04/2025", "2025-04-30", "2025-04-30"},
+	}
+
+	for _, tt := range tests {
+		start, end, err := ParseTanggalRange(tt.text)
+		if err != nil {
+			t.Errorf("ParseTanggalRange(%q) failed: %v", tt.text, err)
+			continue
+		}
+		if got := start.Format(ISODate); got != tt.start {
+			t.Errorf("ParseTanggalRange(%q) start = %s, want %s", tt.text, got, tt.start)
+		}
+		if got := end.Format(ISODate); got != tt.end {
+			t.Errorf("ParseTanggalRange(%q) end = %s, want %s", tt.text, got, tt.end)
+		}
+	}
+}
+
+func TestParseTanggalRangeInvalid(t *testing.T) {
+	for _, text := range []string{"", "Akan diumumkan", "31 Februari 2025", "5 - 1 Maret 2025", "10 Maret"} {
+		if _, _, err := ParseTanggalRange(text); err == nil {
+			t.Errorf("ParseTanggalRange(%q) succeeded, want error", text)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yafyx/baak-api/models"
+)
+
+// UjianWaktuTable is the kuliahUjian table that maps exam sessions to times
+const UjianWaktuTable = 5
+
+// hariIndonesia holds the day names BAAK uses, indexed by time.Weekday
+var hariIndonesia = [...]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jum'at", "Sabtu"}
+
+var (
+	ujianHari    = regexp.MustCompile(`(?i)\b(senin|selasa|rabu|kamis|jum'?at|sabtu|minggu)\b`)
+	ujianTanggal = regexp.MustCompile(`\d{1,2}[/.]\d{1,2}[/.]\d{4}|\d{1,2}\s+[A-Za-z]+\.?\s+\d{4}`)
+	ujianJam     = regexp.MustCompile(`(\d{1,2})[.:](\d{2})\s*(?:-|–|s\.?/?d\.?)\s*(\d{1,2})[.:](\d{2})`)
+	ujianSesi    = regexp.MustCompile(`(?i)\bsesi\s*(?:ke\s*-?\s*)?(\d+)`)
+)
+
+// ujianWaktu is the structured form of an exam's Waktu cell
+type ujianWaktu struct {
+	Hari    string
+	Tanggal string
+	Mulai   string
+	Selesai string
+	Sesi    int
+}
+
+// parseUjianWaktu breaks a cell such as "Senin, 28 April 2025 / 07.30 -
+// 09.00" or "Selasa, 29/04/2025 Sesi 2" into its parts. Parts that cannot be
+// found are left empty; the times of a session are filled in later from the
+// kuliahUjian table.
+func parseUjianWaktu(text string) ujianWaktu {
+	var result ujianWaktu
+
+	rest := text
+	if match := ujianTanggal.FindString(rest); match != "" {
+		if tanggal, err := ParseTanggal(match); err == nil {
+			result.Tanggal = tanggal.Format(ISODate)
+			result.Hari = hariIndonesia[tanggal.Weekday()]
+		}
+		rest = strings.Replace(rest, match, " ", 1)
+	}
+
+	// A day name written in the cell wins over the one derived from the date
+	if m := ujianHari.FindStringSubmatch(rest); m != nil {
+		result.Hari = normalizeHari(m[1])
+	}
+
+	if m := ujianJam.FindStringSubmatch(rest); m != nil {
+		result.Mulai = formatClock(m[1], m[2])
+		result.Selesai = formatClock(m[3], m[4])
+	}
+
+	if m := ujianSesi.FindStringSubmatch(rest); m != nil {
+		result.Sesi, _ = strconv.Atoi(m[1])
+	}
+
+	return result
+}
+
+// normalizeHari spells a day name the way the jadwal pages do
+func normalizeHari(hari string) string {
+	hari = strings.ToLower(hari)
+	for _, name := range hariIndonesia {
+		if strings.ReplaceAll(strings.ToLower(name), "'", "") == strings.ReplaceAll(hari, "'", "") {
+			return name
+		}
+	}
+	return hari
+}
+
+// formatClock turns the hour and minute of "7.30" into "07:30", the form
+// the kuliahUjian tables use
+func formatClock(hour, minute string) string {
+	h, _ := strconv.Atoi(hour)
+	return fmt.Sprintf("%02d:%s", h, minute)
+}
+
+// resolveUjianWaktu fills in the structured time fields of each exam,
+// looking up session numbers in the exam time-slot table when the cell does
+// not give the times itself. The rows are still usable without that table,
+// so when it cannot be loaded the session times are left empty.
+func (s *Scraper) resolveUjianWaktu(ctx context.Context, ujianList []models.Ujian) {
+	var slots []models.WaktuSlot
+	slotsLoaded := false
+	for i := range ujianList {
+		waktu := parseUjianWaktu(ujianList[i].Waktu)
+
+		if waktu.Mulai == "" && waktu.Sesi > 0 {
+			if !slotsLoaded {
+				slotsLoaded = true
+				var err error
+				if slots, err = s.Waktu(ctx, UjianWaktuTable); err != nil {
+					log.Printf("Failed to load exam time-slot table %d, leaving session times empty: %v", UjianWaktuTable, err)
+				}
+			}
+			if slot, ok := findWaktuSlot(slots, waktu.Sesi); ok {
+				waktu.Mulai = slot.Mulai
+				waktu.Selesai = slot.Selesai
+			}
+		}
+
+		ujianList[i].Hari = waktu.Hari
+		ujianList[i].Tanggal = waktu.Tanggal
+		ujianList[i].Mulai = waktu.Mulai
+		ujianList[i].Selesai = waktu.Selesai
+		ujianList[i].Sesi = waktu.Sesi
+	}
+}
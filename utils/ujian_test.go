@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseUjianWaktu(t *testing.T) {
+	tests := map[string]ujianWaktu{
+		"Senin, 28 April 2025 / 07.30 - 09.00": {Hari: "Senin", Tanggal: "2025-04-28", Mulai: "07:30", Selesai: "09:00"},
+		"Selasa, 29 April 2025 Sesi 2":         {Hari: "Selasa", Tanggal: "2025-04-29", Sesi: 2},
+		"Rabu, 30/04/2025 / 10.30 - 12.00":     {Hari: "Rabu", Tanggal: "2025-04-30", Mulai: "10:30", Selesai: "12:00"},
+		"2 Mei 2025 sesi ke-3":                 {Hari: "Jum'at", Tanggal: "2025-05-02", Sesi: 3},
+		"JUMAT 8.00 s/d 9.30":                  {Hari: "Jum'at", Mulai: "08:00", Selesai: "09:30"},
+		"Akan diumumkan":                       {},
+	}
+	for text, want := range tests {
+		if got := parseUjianWaktu(text); got != want {
+			t.Errorf("parseUjianWaktu(%q) = %+v, want %+v", text, got, want)
+		}
+	}
+}
+
+func TestGetUTSWithoutSessionTable(t *testing.T) {
+	// Only the search page is available, not /kuliahUjian/5
+	dir := t.TempDir()
+	page, err := os.ReadFile(filepath.Join("testdata", "jadwal_cariUts__teks_2IA01.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "jadwal_cariUts__teks_2IA01.html"), page, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	utsList, err := NewScraper(NewFileFetcher(dir)).GetUTS(context.Background(), BaseURL+"/jadwal/cariUts?&teks=2IA01")
+	if err != nil {
+		t.Fatalf("GetUTS failed: %v", err)
+	}
+	if len(utsList) != 3 {
+		t.Fatalf("expected 3 exams, got %d", len(utsList))
+	}
+	if sesi := utsList[1]; sesi.Sesi != 2 || sesi.Mulai != "" || sesi.Selesai != "" {
+		t.Errorf("unexpected session exam: %+v", sesi.Ujian)
+	}
+	if utsList[0].Mulai != "07:30" {
+		t.Errorf("explicit times should still be parsed, got %+v", utsList[0].Ujian)
+	}
+}
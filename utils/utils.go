@@ -12,12 +12,14 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/yafyx/baak-api/config"
 	"github.com/yafyx/baak-api/models"
 	"golang.org/x/net/publicsuffix"
 	"golang.org/x/time/rate"
@@ -105,11 +107,78 @@ var acceptLanguages = []string{
 	"id-ID,id;q=0.9,en-US;q=0.8,en;q=0.7",
 }
 
-// Simulate human-like delays
-func humanDelay() {
-	// Random delay between 1-3 seconds to simulate human interaction
+// ErrRequestCanceled is returned when the caller's context ends before the
+// upstream fetch completes. The context error is wrapped alongside it.
+var ErrRequestCanceled = errors.New("upstream request canceled")
+
+func canceledError(ctx context.Context) error {
+	err := fmt.Errorf("%w: %w", ErrRequestCanceled, ctx.Err())
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{Err: err}
+	}
+	return err
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if ctx.Err() != nil {
+		return canceledError(ctx)
+	}
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return canceledError(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Random delay between 1-3 seconds to simulate human interaction
+func humanDelayDuration() time.Duration {
 	delay := 1000 + rand.Intn(2000)
-	time.Sleep(time.Duration(delay) * time.Millisecond)
+	return time.Duration(delay) * time.Millisecond
+}
+
+// Simulate human-like delays, skipping them when the request deadline
+// leaves no room for both the delay and a fetch attempt
+func humanDelay(ctx context.Context) error {
+	delay := humanDelayDuration()
+	if !hasBudget(ctx, delay) {
+		return nil
+	}
+	return sleepContext(ctx, delay)
+}
+
+// minAttemptBudget is the least amount of time worth spending on a single
+// upstream request; attempts with less time left are skipped
+const minAttemptBudget = 3 * time.Second
+
+// ErrUpstreamBudgetExhausted is returned when the request deadline leaves no
+// time for another upstream attempt
+var ErrUpstreamBudgetExhausted = errors.New("upstream budget exhausted")
+
+// hasBudget reports whether waiting for wait still leaves enough time before
+// the context deadline for a fetch attempt. Contexts without a deadline
+// always have budget.
+func hasBudget(ctx context.Context, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	return time.Until(deadline)-wait >= minAttemptBudget
+}
+
+func budgetExhaustedError(attempts int, lastErr error) error {
+	if lastErr == nil {
+		return &TimeoutError{Err: fmt.Errorf("%w: no time left for an upstream request", ErrUpstreamBudgetExhausted)}
+	}
+	return &TimeoutError{Err: fmt.Errorf("%w after %d attempts: %v", ErrUpstreamBudgetExhausted, attempts, lastErr)}
 }
 
 // getClient returns an HTTP client, potentially with a different proxy
@@ -175,8 +244,8 @@ func directIPRequest() error {
 }
 
 // simpleRequest makes a very basic request to the given URL
-func simpleRequest(targetURL string) error {
-	req, err := http.NewRequest("GET", targetURL, nil)
+func simpleRequest(ctx context.Context, targetURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
@@ -199,7 +268,7 @@ func simpleRequest(targetURL string) error {
 }
 
 // Warm up the session by visiting the homepage first
-func ensureSession() error {
+func ensureSession(ctx context.Context) error {
 	// Visit the homepage first to establish cookies if we haven't done so already
 	baseUrl, err := url.Parse(BaseURL)
 	if err != nil {
@@ -215,7 +284,7 @@ func ensureSession() error {
 		fmt.Println("[DEBUG] No cookies found, trying to establish session")
 
 		// Try HTTP first (some sites redirect HTTP to HTTPS)
-		err := simpleRequest("http://baak.gunadarma.ac.id")
+		err := simpleRequest(ctx, "http://baak.gunadarma.ac.id")
 		if err == nil {
 			// Check if we got cookies
 			clientMutex.RLock()
@@ -224,6 +293,7 @@ func ensureSession() error {
 
 			if hasCookies {
 				fmt.Println("[DEBUG] Session established using HTTP request")
+				newSession()
 				return nil
 			}
 		} else {
@@ -231,7 +301,11 @@ func ensureSession() error {
 		}
 
 		// Try HTTPS
-		err = simpleRequest(BaseURL)
+		if ctx.Err() != nil {
+			return canceledError(ctx)
+		}
+
+		err = simpleRequest(ctx, BaseURL)
 		if err == nil {
 			// Check if we got cookies
 			clientMutex.RLock()
@@ -240,12 +314,17 @@ func ensureSession() error {
 
 			if hasCookies {
 				fmt.Println("[DEBUG] Session established using HTTPS request")
+				newSession()
 				return nil
 			}
 		} else {
 			fmt.Printf("[DEBUG] simpleRequest(HTTPS: %s) failed: %v\n", BaseURL, err)
 		}
 
+		if ctx.Err() != nil {
+			return canceledError(ctx)
+		}
+
 		// Try direct IP access as a last resort
 		// fmt.Println("[DEBUG] Trying direct IP access method")
 		// err = directIPRequest()
@@ -260,7 +339,10 @@ func ensureSession() error {
 		hasCookiesAfterAttempts := len(httpClient.Jar.Cookies(baseUrl)) > 0
 		clientMutex.RUnlock()
 		if !hasCookiesAfterAttempts {
-			return fmt.Errorf("failed to establish session after trying HTTP, HTTPS (check logs for details)")
+			return &UnreachableError{
+				URL: BaseURL,
+				Err: fmt.Errorf("failed to establish session after trying HTTP, HTTPS (check logs for details)"),
+			}
 		}
 
 	} else {
@@ -271,10 +353,11 @@ func ensureSession() error {
 }
 
 // Fetch a document with proper referrer and headers
-func FetchDocumentWithRetry(url string, referrer string, maxRetries int) (*goquery.Document, error) {
+func FetchDocumentWithRetry(ctx context.Context, url string, referrer string, maxRetries int) (*goquery.Document, error) {
 	backoffFactor := 2.0
 	initialBackoff := 1 * time.Second
 	var lastErr error
+	var backoffTime time.Duration
 
 	// Use a default referrer if none provided
 	if referrer == "" {
@@ -289,70 +372,63 @@ func FetchDocumentWithRetry(url string, referrer string, maxRetries int) (*goque
 	client := getClient()
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		// Add human-like delay between attempts
+		// Plan the backoff and human-like delay before spending it, so an
+		// attempt that cannot finish before the deadline is never started
+		wait := backoffTime
 		if attempt > 0 {
-			humanDelay()
-
-			// For retry attempts, try to get a fresh client with potentially different proxy
-			if attempt > 1 {
-				client = getClient()
-			}
+			wait += humanDelayDuration()
+		}
+		if !hasBudget(ctx, wait) {
+			return nil, budgetExhaustedError(attempt, lastErr)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
 		}
 
-		// Create a context with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-		defer cancel()
+		// For retry attempts, try to get a fresh client with potentially different proxy
+		if attempt > 1 {
+			client = getClient()
+		}
 
-		// Create a new request
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %v", err)
-		}
-
-		// Randomize User-Agent and other headers
-		userAgent := userAgents[rand.Intn(len(userAgents))]
-		acceptLang := acceptLanguages[rand.Intn(len(acceptLanguages))]
-
-		req.Header.Set("User-Agent", userAgent)
-		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
-		req.Header.Set("Accept-Language", acceptLang)
-		req.Header.Set("Connection", "keep-alive")
-		req.Header.Set("Upgrade-Insecure-Requests", "1")
-		req.Header.Set("Referer", referrer)
-		req.Header.Set("Sec-Fetch-Dest", "document")
-		req.Header.Set("Sec-Fetch-Mode", "navigate")
-		req.Header.Set("Sec-Fetch-Site", "same-origin")
-		req.Header.Set("Sec-Fetch-User", "?1")
-		req.Header.Set("Cache-Control", "max-age=0")
-
-		// Add a pseudo-random request ID to make each request unique
-		req.Header.Set("X-Request-ID", fmt.Sprintf("%d", time.Now().UnixNano()))
-
-		// Execute the request
-		res, err := client.Do(req)
+		statusCode, header, body, err := fetchAttempt(ctx, client, url, referrer)
 		if err != nil {
-			lastErr = fmt.Errorf("failed to fetch URL: %v", err)
-			backoffTime := time.Duration(float64(initialBackoff) * (backoffFactor * float64(attempt)))
-			time.Sleep(backoffTime)
+			// Stop retrying as soon as the caller has gone away
+			if ctx.Err() != nil {
+				return nil, canceledError(ctx)
+			}
+			lastErr = &UnreachableError{URL: url, Err: err}
+			if isTimeout(err) {
+				lastErr = &TimeoutError{URL: url, Err: err}
+			}
+			backoffTime = time.Duration(float64(initialBackoff) * (backoffFactor * float64(attempt)))
 			continue
 		}
-		defer res.Body.Close()
+
+		// Challenge pages will not go away by retrying, so report them right away
+		if isChallengeResponse(header, body) {
+			recordChallenge(url)
+			return nil, &ChallengeError{URL: url, RetryAfter: challengeRetryAfter(header)}
+		}
+
+		// An expired token will not become valid again, let the caller refresh it
+		if statusCode == StatusPageExpired {
+			return nil, &UpstreamStatusError{URL: url, StatusCode: statusCode}
+		}
 
 		// Handle response based on status code
-		if res.StatusCode != http.StatusOK {
-			if res.StatusCode == http.StatusForbidden {
-				lastErr = fmt.Errorf("access forbidden (403): the server might be restricting access or detecting automated requests")
+		if statusCode != http.StatusOK {
+			if statusCode == http.StatusForbidden {
+				// The server might be restricting access or detecting automated requests
+				lastErr = &UpstreamStatusError{URL: url, StatusCode: statusCode}
 				// For 403 errors, use a longer backoff with random jitter
 				jitter := float64(1.0 + (rand.Float64() * 0.5)) // 1.0-1.5 jitter factor
-				backoffTime := time.Duration(float64(initialBackoff*3) * (backoffFactor * float64(attempt) * jitter))
-				time.Sleep(backoffTime)
+				backoffTime = time.Duration(float64(initialBackoff*3) * (backoffFactor * float64(attempt) * jitter))
 				continue
 			}
 
-			lastErr = fmt.Errorf("unexpected status code: %d %s", res.StatusCode, res.Status)
+			lastErr = &UpstreamStatusError{URL: url, StatusCode: statusCode}
 			if attempt < maxRetries-1 {
-				backoffTime := time.Duration(float64(initialBackoff) * (backoffFactor * float64(attempt)))
-				time.Sleep(backoffTime)
+				backoffTime = time.Duration(float64(initialBackoff) * (backoffFactor * float64(attempt)))
 				continue
 			}
 			return nil, lastErr
@@ -365,34 +441,80 @@ func FetchDocumentWithRetry(url string, referrer string, maxRetries int) (*goque
 		}
 		visitedPages = append(visitedPages, url)
 
-		// Successfully got a 200 OK response, parse the document
-		doc, err := goquery.NewDocumentFromReader(res.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse HTML: %v", err)
-		}
+		// Successfully got a 200 OK response, parse it
+		clearChallenge()
 
-		return doc, nil
+		return parseDocument(url, body)
 	}
 
 	// If we got here, all attempts failed
-	return nil, fmt.Errorf("all retry attempts failed: %v", lastErr)
+	return nil, fmt.Errorf("all retry attempts failed: %w", lastErr)
 }
 
-func FetchDocument(url string) (*goquery.Document, error) {
+// fetchAttempt performs a single GET request bounded by a per-attempt timeout
+// derived from ctx, returning the status code, headers and the response body.
+func fetchAttempt(ctx context.Context, client *http.Client, url string, referrer string) (int, http.Header, []byte, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// Create a new request
+	req, err := http.NewRequestWithContext(attemptCtx, "GET", url, nil)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	// Randomize User-Agent and other headers
+	userAgent := userAgents[rand.Intn(len(userAgents))]
+	acceptLang := acceptLanguages[rand.Intn(len(acceptLanguages))]
+
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8")
+	req.Header.Set("Accept-Language", acceptLang)
+	req.Header.Set("Connection", "keep-alive")
+	req.Header.Set("Upgrade-Insecure-Requests", "1")
+	req.Header.Set("Referer", referrer)
+	req.Header.Set("Sec-Fetch-Dest", "document")
+	req.Header.Set("Sec-Fetch-Mode", "navigate")
+	req.Header.Set("Sec-Fetch-Site", "same-origin")
+	req.Header.Set("Sec-Fetch-User", "?1")
+	req.Header.Set("Cache-Control", "max-age=0")
+
+	// Add a pseudo-random request ID to make each request unique
+	req.Header.Set("X-Request-ID", fmt.Sprintf("%d", time.Now().UnixNano()))
+
+	// Execute the request
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer res.Body.Close()
+
+	// Error bodies are kept too so challenge pages can be recognized
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return res.StatusCode, res.Header, body, nil
+}
+
+func FetchDocument(ctx context.Context, url string) (*goquery.Document, error) {
 	// Ensure we have an active session
-	if err := ensureSession(); err != nil {
+	if err := ensureSession(ctx); err != nil {
 		return nil, err
 	}
 
 	// Add slight random delay to mimic human behavior
-	humanDelay()
+	if err := humanDelay(ctx); err != nil {
+		return nil, err
+	}
 
-	return FetchDocumentWithRetry(url, "", 5) // Increase max retries to 5
+	return FetchDocumentWithRetry(ctx, url, "", 5) // Increase max retries to 5
 }
 
 // GetCSRFToken fetches a page and extracts the CSRF token from a hidden input field.
-func GetCSRFToken(url string) (string, error) {
-	doc, err := FetchDocument(url)
+func (s *Scraper) GetCSRFToken(ctx context.Context, url string) (string, error) {
+	doc, err := s.fetch(ctx, url)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch document for CSRF token: %w", err)
 	}
@@ -402,184 +524,434 @@ func GetCSRFToken(url string) (string, error) {
 		// Optionally log the HTML body here for debugging if token is not found
 		// html, _ := doc.Html()
 		// fmt.Println("DEBUG: HTML body:\n", html)
-		return "", errors.New("CSRF token input field not found on page: " + url)
+		return "", &CSRFTokenMissingError{URL: url}
 	}
 
 	return token, nil
 }
 
-func GetJadwal(url string) (models.Jadwal, error) {
-	doc, err := FetchDocument(url)
+// Column layouts of the BAAK result tables, listed in the order the cells
+// appear so they can still be read by position when the header is missing
+var (
+	jadwalColumns = []Column{
+		{Name: "No", Aliases: []string{"Nomor"}},
+		{Name: "Kelas", Optional: true},
+		{Name: "Hari"},
+		{Name: "Mata Kuliah", Aliases: []string{"Matakuliah", "Nama Mata Kuliah", "MK"}},
+		{Name: "Waktu", Aliases: []string{"Jam", "Jam Ke"}},
+		{Name: "Ruang", Aliases: []string{"Ruangan"}},
+		{Name: "Dosen", Aliases: []string{"Nama Dosen", "Pengajar"}},
+	}
+	ujianColumns = []Column{
+		{Name: "No", Aliases: []string{"Nomor"}},
+		{Name: "Mata Kuliah", Aliases: []string{"Matakuliah", "Nama Mata Kuliah", "MK"}},
+		{Name: "Waktu", Aliases: []string{"Hari/Tanggal", "Tanggal", "Jadwal"}},
+		{Name: "Ruang", Aliases: []string{"Ruangan"}},
+		{Name: "Dosen", Aliases: []string{"Nama Dosen", "Pengajar"}},
+	}
+	kelasBaruColumns = []Column{
+		{Name: "No", Aliases: []string{"Nomor"}},
+		{Name: "NPM"},
+		{Name: "Nama", Aliases: []string{"Nama Mahasiswa"}},
+		{Name: "Kelas Lama"},
+		{Name: "Kelas Baru"},
+	}
+	mahasiswaBaruColumns = []Column{
+		{Name: "No", Aliases: []string{"Nomor"}},
+		{Name: "No Pend", Aliases: []string{"No Pendaftaran", "Nomor Pendaftaran", "No. Pend"}},
+		{Name: "Nama", Aliases: []string{"Nama Mahasiswa"}},
+		{Name: "NPM"},
+		{Name: "Kelas"},
+		{Name: "Keterangan", Aliases: []string{"Ket"}},
+	}
+)
+
+// checkTable runs the shape checks shared by the header-driven parsers
+func checkTable(parser, url string, sel *goquery.Selection, table Table) *shapeCheck {
+	check := newShapeCheck(parser, url)
+	if check.requireTable(sel) {
+		check.checkExtracted(sel, table)
+	}
+	return check
+}
+
+// GetJadwal returns every class in the search result merged into one week,
+// with the kelas of each entry in MataKuliah.Kelas
+func (s *Scraper) GetJadwal(ctx context.Context, url string) (models.Jadwal, error) {
+	entries, err := s.getJadwalEntries(ctx, url)
 	if err != nil {
 		return models.Jadwal{}, err
 	}
 
 	jadwal := models.Jadwal{}
-	hariMap := map[string]*[]models.MataKuliah{
-		"Senin":  &jadwal.Senin,
-		"Selasa": &jadwal.Selasa,
-		"Rabu":   &jadwal.Rabu,
-		"Kamis":  &jadwal.Kamis,
-		"Jum'at": &jadwal.Jumat,
-		"Sabtu":  &jadwal.Sabtu,
+	for _, entry := range entries {
+		addToJadwal(&jadwal, entry.hari, entry.mataKuliah)
 	}
+	return jadwal, nil
+}
 
-	timeStampLUT, err := GetTimeStampLUT()
+// GetJadwalPerKelas returns the search result as one week per kelas, in the
+// order the classes appear on the page
+func (s *Scraper) GetJadwalPerKelas(ctx context.Context, url string) ([]models.JadwalKelas, error) {
+	entries, err := s.getJadwalEntries(ctx, url)
 	if err != nil {
-		return models.Jadwal{}, err
+		return nil, err
 	}
 
-	doc.Find("table").First().Find("tr").Each(func(i int, row *goquery.Selection) {
-		cells := row.Find("td")
-		if cells.Length() < 5 {
-			return
+	result := []models.JadwalKelas{}
+	index := make(map[string]int)
+	for _, entry := range entries {
+		kelas := entry.mataKuliah.Kelas
+		i, ok := index[kelas]
+		if !ok {
+			i = len(result)
+			index[kelas] = i
+			result = append(result, models.JadwalKelas{Kelas: kelas})
 		}
+		// The kelas is already on the group
+		entry.mataKuliah.Kelas = ""
+		addToJadwal(&result[i].Jadwal, entry.hari, entry.mataKuliah)
+	}
+	return result, nil
+}
 
-		hari := strings.TrimSpace(cells.Eq(1).Text())
-		waktu := strings.TrimSpace(cells.Eq(3).Text())
-		jam := convertWaktuToJam(waktu, timeStampLUT)
+// configuredBaseURL is the BAAK address from BASE_URL, or BaseURL when the
+// configuration has not been loaded
+func configuredBaseURL() string {
+	if config.AppConfig.BaseURL != "" {
+		return config.AppConfig.BaseURL
+	}
+	return BaseURL
+}
 
-		mataKuliah := models.MataKuliah{
-			Nama:  strings.TrimSpace(cells.Eq(2).Text()),
-			Waktu: waktu,
-			Jam:   jam,
-			Ruang: strings.TrimSpace(cells.Eq(4).Text()),
-			Dosen: strings.TrimSpace(cells.Eq(5).Text()),
-		}
+// WithJadwalSearch calls fetch with the URL of a cariJadKul search carrying
+// the session's CSRF token
+func (s *Scraper) WithJadwalSearch(ctx context.Context, search string, fetch func(searchURL string) error) error {
+	baseURL := configuredBaseURL()
+
+	// The CSRF token comes from the base jadwal page
+	return s.WithCSRFToken(ctx, []string{baseURL + "/jadwal"}, func(token string) error {
+		searchURL := fmt.Sprintf("%s/jadwal/cariJadKul?_token=%s&teks=%s",
+			baseURL,
+			url.QueryEscape(token),
+			url.QueryEscape(search),
+		)
+		return fetch(searchURL)
+	})
+}
 
-		if hariSlice, ok := hariMap[hari]; ok {
-			*hariSlice = append(*hariSlice, mataKuliah)
-		}
+// SearchJadwal runs a cariJadKul search and merges every class into one week
+func (s *Scraper) SearchJadwal(ctx context.Context, search string) (models.Jadwal, error) {
+	var jadwal models.Jadwal
+	err := s.WithJadwalSearch(ctx, search, func(searchURL string) error {
+		var err error
+		jadwal, err = s.GetJadwal(ctx, searchURL)
+		return err
 	})
+	return jadwal, err
+}
 
-	return jadwal, nil
+// jadwalEntry is a parsed cariJadKul row with a known day
+type jadwalEntry struct {
+	hari       string
+	mataKuliah models.MataKuliah
 }
 
-func GetTimeStampLUT() ([][]string, error) {
-	doc, err := FetchDocument(BaseURL + "/kuliahUjian/6")
+// kelasCode matches class codes such as "2IA01" in headings
+var kelasCode = regexp.MustCompile(`\b[1-9][A-Z]{2,3}\d{2}\b`)
+
+// getJadwalEntries reads every result table of a cariJadKul page. The kelas
+// of a row comes from its Kelas column or, when the table has none, from the
+// heading before the table.
+func (s *Scraper) getJadwalEntries(ctx context.Context, url string) ([]jadwalEntry, error) {
+	doc, err := s.fetch(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
-	var result [][]string
-	doc.Find("table.cell-xs-6 tr").Each(func(i int, row *goquery.Selection) {
-		cells := row.Find("td")
-		if cells.Length() >= 2 {
-			timeRange := strings.TrimSpace(cells.Eq(1).Text())
-			timeRange = strings.ReplaceAll(timeRange, " ", "")
-			timeRange = strings.ReplaceAll(timeRange, ".", ":")
-			times := strings.Split(timeRange, "-")
-			if len(times) == 2 {
-				result = append(result, times)
+	waktuSlots, err := s.Waktu(ctx, KuliahWaktuTable)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := doc.Find("table")
+	firstTable := ExtractTable(tables.First(), jadwalColumns)
+	check := checkTable("jadwal", url, tables.First(), firstTable)
+	defer check.done()
+
+	var entries []jadwalEntry
+	rowCount := 0
+	tables.Each(func(i int, tableSel *goquery.Selection) {
+		table := firstTable
+		if i > 0 {
+			// Later tables only count when they look like result tables
+			if table = ExtractTable(tableSel, jadwalColumns); !table.HeaderFound {
+				return
 			}
+			check.checkTableAt(i+1, tableSel, table)
+		}
+		rowCount += len(table.Rows)
+
+		heading := tableSel.PrevAllFiltered("h1, h2, h3, h4, h5, h6").First().Text()
+		headingKelas := kelasCode.FindString(strings.ToUpper(heading))
+
+		for _, row := range table.Rows {
+			hari := row.Get("Hari")
+			if !isHari(hari) {
+				continue
+			}
+
+			kelas := row.Get("Kelas")
+			if kelas == "" {
+				kelas = headingKelas
+			}
+
+			waktu := row.Get("Waktu")
+			resolved := resolveWaktu(waktu, waktuSlots)
+
+			jam := ""
+			if resolved.Mulai != "" {
+				jam = resolved.Mulai + " - " + resolved.Selesai
+			}
+
+			entries = append(entries, jadwalEntry{
+				hari: hari,
+				mataKuliah: models.MataKuliah{
+					Kelas:    kelas,
+					Nama:     row.Get("Mata Kuliah"),
+					Waktu:    waktu,
+					Jam:      jam,
+					Periode:  resolved.Periode,
+					Mulai:    resolved.Mulai,
+					Selesai:  resolved.Selesai,
+					Durasi:   resolved.Durasi,
+					Ruang:    row.Get("Ruang"),
+					Dosen:    row.Get("Dosen"),
+					Pengajar: ParseDosen(row.Get("Dosen")),
+				},
+			})
 		}
 	})
 
-	return result, nil
+	if rowCount > 0 && len(entries) == 0 {
+		check.addf("no rows matched a known day")
+	}
+
+	return entries, nil
 }
 
-func convertWaktuToJam(waktu string, timeStampLUT [][]string) string {
-	re := regexp.MustCompile(`(\d+)`)
-	matches := re.FindAllString(waktu, -1)
+// jadwalHari lists the days models.Jadwal holds, as cariJadKul spells them
+var jadwalHari = []string{"Senin", "Selasa", "Rabu", "Kamis", "Jum'at", "Sabtu"}
 
-	if len(matches) < 2 || len(matches) > 3 || len(timeStampLUT) == 0 {
-		return ""
+func isHari(hari string) bool {
+	for _, name := range jadwalHari {
+		if name == hari {
+			return true
+		}
 	}
+	return false
+}
 
-	start, _ := strconv.Atoi(matches[0])
-	end, _ := strconv.Atoi(matches[len(matches)-1])
+// jadwalDays maps the day names in jadwalHari to the days of jadwal
+func jadwalDays(jadwal *models.Jadwal) map[string]*[]models.MataKuliah {
+	return map[string]*[]models.MataKuliah{
+		"Senin":  &jadwal.Senin,
+		"Selasa": &jadwal.Selasa,
+		"Rabu":   &jadwal.Rabu,
+		"Kamis":  &jadwal.Kamis,
+		"Jum'at": &jadwal.Jumat,
+		"Sabtu":  &jadwal.Sabtu,
+	}
+}
 
-	if start < 1 || start > len(timeStampLUT) || end < 1 || end > len(timeStampLUT) {
-		return ""
+// addToJadwal appends mataKuliah to the day of jadwal named hari
+func addToJadwal(jadwal *models.Jadwal, hari string, mataKuliah models.MataKuliah) {
+	if hariSlice, ok := jadwalDays(jadwal)[hari]; ok {
+		*hariSlice = append(*hariSlice, mataKuliah)
 	}
+}
+
+// KuliahWaktuTable is the kuliahUjian table that maps lecture periods to times
+const KuliahWaktuTable = 6
+
+// waktuParsers names the drift status of each known kuliahUjian table
+var waktuParsers = map[int]string{
+	KuliahWaktuTable: "waktu_kuliah",
+	UjianWaktuTable:  "waktu_ujian",
+}
 
-	return timeStampLUT[start-1][0] + " - " + timeStampLUT[end-1][1]
+// IsWaktuTable reports whether tabel is a kuliahUjian table this API knows
+func IsWaktuTable(tabel int) bool {
+	_, ok := waktuParsers[tabel]
+	return ok
 }
 
-func GetKegiatan(url string) ([]models.Kegiatan, error) {
-	doc, err := FetchDocument(url)
+// GetWaktu scrapes the period→time table published at /kuliahUjian/{tabel}
+func (s *Scraper) GetWaktu(ctx context.Context, tabel int) ([]models.WaktuSlot, error) {
+	url := fmt.Sprintf("%s/kuliahUjian/%d", BaseURL, tabel)
+	doc, err := s.fetch(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
-	var kegiatanList []models.Kegiatan
-	var parentKegiatan string
+	tableSel := doc.Find("table.cell-xs-6")
+	if tableSel.Length() == 0 {
+		tableSel = doc.Find("table")
+	}
+	rows := tableSel.Find("tr")
+
+	// Only the known tables are shape-checked, so other table numbers
+	// cannot mark the parser as drifted
+	var result []models.WaktuSlot
+	if parser, ok := waktuParsers[tabel]; ok {
+		check := newShapeCheck(parser, url)
+		defer check.done()
+		if check.requireTable(tableSel) {
+			defer func() {
+				check.checkRows(len(result), 1, countDataRows(tableSel))
+			}()
+		}
+	}
 
-	doc.Find("table").First().Find("tr").Each(func(i int, row *goquery.Selection) {
+	rows.Each(func(i int, row *goquery.Selection) {
 		cells := row.Find("td")
-		if cells.Length() == 2 {
-			kegiatanText := strings.TrimSpace(cells.Eq(0).Text())
-			tanggalText := strings.TrimSpace(cells.Eq(1).Text())
-
-			if tanggalText == "" {
-				parentKegiatan = kegiatanText
+		if cells.Length() >= 2 {
+			timeRange := strings.TrimSpace(cells.Eq(1).Text())
+			timeRange = strings.ReplaceAll(timeRange, " ", "")
+			timeRange = strings.ReplaceAll(timeRange, ".", ":")
+			times := strings.Split(timeRange, "-")
+			if len(times) != 2 {
 				return
 			}
 
-			start, end := parseTanggal(tanggalText)
-
-			fullKegiatan := kegiatanText
-			if parentKegiatan != "" && isSubItem(kegiatanText) {
-				fullKegiatan = parentKegiatan + " " + kegiatanText
-			} else {
-				parentKegiatan = ""
+			// Number rows by position when the period cell is not numeric
+			periode, err := strconv.Atoi(strings.TrimSpace(cells.Eq(0).Text()))
+			if err != nil {
+				periode = len(result) + 1
 			}
 
-			kegiatan := models.Kegiatan{
-				Kegiatan: fullKegiatan,
-				Tanggal:  tanggalText,
-				Start:    start,
-				End:      end,
-			}
-			kegiatanList = append(kegiatanList, kegiatan)
-		} else {
-			parentKegiatan = ""
+			result = append(result, models.WaktuSlot{
+				Periode: periode,
+				Mulai:   times[0],
+				Selesai: times[1],
+			})
 		}
 	})
 
-	return kegiatanList, nil
+	return result, nil
+}
+
+// findWaktuSlot looks up the slot for a period number
+func findWaktuSlot(slots []models.WaktuSlot, periode int) (models.WaktuSlot, bool) {
+	for _, slot := range slots {
+		if slot.Periode == periode {
+			return slot, true
+		}
+	}
+	return models.WaktuSlot{}, false
+}
+
+// jadwalWaktu is a lecture's period list resolved through the time-slot table
+type jadwalWaktu struct {
+	Periode []int
+	Mulai   string
+	Selesai string
+	Durasi  int
 }
 
-func isSubItem(text string) bool {
-	return regexp.MustCompile(`^[a-z]\..+`).MatchString(text)
+// resolveWaktu turns a period list such as "1/2/3" or "1/2/4" into its start
+// and end time and the total minutes of the listed periods. Periods missing
+// from the table leave the times empty but are still returned.
+func resolveWaktu(waktu string, slots []models.WaktuSlot) jadwalWaktu {
+	re := regexp.MustCompile(`\d+`)
+
+	seen := make(map[int]bool)
+	var result jadwalWaktu
+	for _, match := range re.FindAllString(waktu, -1) {
+		periode, err := strconv.Atoi(match)
+		if err != nil || seen[periode] {
+			continue
+		}
+		seen[periode] = true
+		result.Periode = append(result.Periode, periode)
+	}
+	sort.Ints(result.Periode)
+
+	if len(result.Periode) == 0 {
+		return result
+	}
+
+	durasi := 0
+	for _, periode := range result.Periode {
+		slot, ok := findWaktuSlot(slots, periode)
+		if !ok {
+			return jadwalWaktu{Periode: result.Periode}
+		}
+		minutes, ok := slotMinutes(slot)
+		if !ok {
+			return jadwalWaktu{Periode: result.Periode}
+		}
+		durasi += minutes
+	}
+
+	first, _ := findWaktuSlot(slots, result.Periode[0])
+	last, _ := findWaktuSlot(slots, result.Periode[len(result.Periode)-1])
+	result.Mulai = first.Mulai
+	result.Selesai = last.Selesai
+	result.Durasi = durasi
+	return result
 }
 
-func parseTanggal(tanggal string) (start, end string) {
-	parts := strings.Split(tanggal, "-")
-	if len(parts) == 2 {
-		start = strings.TrimSpace(parts[0])
-		end = strings.TrimSpace(parts[1])
-	} else if len(parts) == 1 {
-		start = strings.TrimSpace(parts[0])
-		end = start
+// slotMinutes returns the length of a time slot in minutes
+func slotMinutes(slot models.WaktuSlot) (int, bool) {
+	mulai, ok := clockMinutes(slot.Mulai)
+	if !ok {
+		return 0, false
+	}
+	selesai, ok := clockMinutes(slot.Selesai)
+	if !ok || selesai < mulai {
+		return 0, false
 	}
+	return selesai - mulai, true
+}
 
-	return start, end
+// clockMinutes parses "HH:MM" into minutes since midnight
+func clockMinutes(clock string) (int, bool) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return parsed.Hour()*60 + parsed.Minute(), true
 }
 
-func GetKelasbaru(baseURL string) ([]models.KelasBaru, error) {
+func (s *Scraper) GetKelasbaru(ctx context.Context, baseURL string) ([]models.KelasBaru, error) {
 	var kelasBaru []models.KelasBaru
 	page := 1
 
 	for {
+		if ctx.Err() != nil {
+			return nil, canceledError(ctx)
+		}
+
 		url := fmt.Sprintf("%s&page=%d", baseURL, page)
-		doc, err := FetchDocument(url)
+		doc, err := s.fetch(ctx, url)
 		if err != nil {
 			return nil, err
 		}
 
-		doc.Find("table").First().Find("tr").Each(func(i int, row *goquery.Selection) {
-			cells := row.Find("td")
-			if cells.Length() == 5 {
-				mhs := models.KelasBaru{
-					NPM:       strings.TrimSpace(cells.Eq(1).Text()),
-					Nama:      strings.TrimSpace(cells.Eq(2).Text()),
-					KelasLama: strings.TrimSpace(cells.Eq(3).Text()),
-					KelasBaru: strings.TrimSpace(cells.Eq(4).Text()),
-				}
-				kelasBaru = append(kelasBaru, mhs)
+		tableSel := doc.Find("table").First()
+		table := ExtractTable(tableSel, kelasBaruColumns)
+		checkTable("kelasbaru", url, tableSel, table).done()
+
+		for _, row := range table.Rows {
+			mhs := models.KelasBaru{
+				NPM:       row.Get("NPM"),
+				Nama:      row.Get("Nama"),
+				KelasLama: row.Get("Kelas Lama"),
+				KelasBaru: row.Get("Kelas Baru"),
 			}
-		})
+			kelasBaru = append(kelasBaru, mhs)
+		}
 
 		if doc.Find(`a[rel="next"]`).Length() == 0 {
 			break
@@ -591,30 +963,35 @@ func GetKelasbaru(baseURL string) ([]models.KelasBaru, error) {
 	return kelasBaru, nil
 }
 
-func GetMahasiswaBaru(url string) ([]models.MahasiswaBaru, error) {
+func (s *Scraper) GetMahasiswaBaru(ctx context.Context, url string) ([]models.MahasiswaBaru, error) {
 	var mahasiswaBaru []models.MahasiswaBaru
 	page := 1
 
 	for {
+		if ctx.Err() != nil {
+			return nil, canceledError(ctx)
+		}
+
 		pageURL := fmt.Sprintf("%s&page=%d", url, page)
-		doc, err := FetchDocument(pageURL)
+		doc, err := s.fetch(ctx, pageURL)
 		if err != nil {
 			return nil, err
 		}
 
-		doc.Find("table").First().Find("tr").Each(func(i int, row *goquery.Selection) {
-			cells := row.Find("td")
-			if cells.Length() == 6 {
-				mhs := models.MahasiswaBaru{
-					NoPend:     strings.TrimSpace(cells.Eq(1).Text()),
-					Nama:       strings.TrimSpace(cells.Eq(2).Text()),
-					NPM:        strings.TrimSpace(cells.Eq(3).Text()),
-					Kelas:      strings.TrimSpace(cells.Eq(4).Text()),
-					Keterangan: strings.TrimSpace(cells.Eq(5).Text()),
-				}
-				mahasiswaBaru = append(mahasiswaBaru, mhs)
+		tableSel := doc.Find("table").First()
+		table := ExtractTable(tableSel, mahasiswaBaruColumns)
+		checkTable("mahasiswabaru", pageURL, tableSel, table).done()
+
+		for _, row := range table.Rows {
+			mhs := models.MahasiswaBaru{
+				NoPend:     row.Get("No Pend"),
+				Nama:       row.Get("Nama"),
+				NPM:        row.Get("NPM"),
+				Kelas:      row.Get("Kelas"),
+				Keterangan: row.Get("Keterangan"),
 			}
-		})
+			mahasiswaBaru = append(mahasiswaBaru, mhs)
+		}
 
 		if doc.Find(`a[rel="next"]`).Length() == 0 {
 			break
@@ -626,30 +1003,74 @@ func GetMahasiswaBaru(url string) ([]models.MahasiswaBaru, error) {
 	return mahasiswaBaru, nil
 }
 
-func GetUTS(url string) ([]models.UTS, error) {
-	doc, err := FetchDocument(url)
+// getUjian parses the exam table shared by the UTS, UAS and UU search pages
+func (s *Scraper) getUjian(ctx context.Context, url, parser string) ([]models.Ujian, error) {
+	doc, err := s.fetch(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
-	var utsList []models.UTS
-	doc.Find("table").First().Find("tr").Each(func(i int, row *goquery.Selection) {
-		cells := row.Find("td")
-		if cells.Length() == 5 {
-			uts := models.UTS{
-				Nama:  strings.TrimSpace(cells.Eq(1).Text()),
-				Waktu: strings.TrimSpace(cells.Eq(2).Text()),
-				Ruang: strings.TrimSpace(cells.Eq(3).Text()),
-				Dosen: strings.TrimSpace(cells.Eq(4).Text()),
-			}
-			utsList = append(utsList, uts)
+	var ujianList []models.Ujian
+	tableSel := doc.Find("table").First()
+	table := ExtractTable(tableSel, ujianColumns)
+	checkTable(parser, url, tableSel, table).done()
+
+	for _, row := range table.Rows {
+		ujian := models.Ujian{
+			Nama:     row.Get("Mata Kuliah"),
+			Waktu:    row.Get("Waktu"),
+			Ruang:    row.Get("Ruang"),
+			Dosen:    row.Get("Dosen"),
+			Pengajar: ParseDosen(row.Get("Dosen")),
 		}
-	})
+		ujianList = append(ujianList, ujian)
+	}
 
+	s.resolveUjianWaktu(ctx, ujianList)
+
+	return ujianList, nil
+}
+
+func (s *Scraper) GetUTS(ctx context.Context, url string) ([]models.UTS, error) {
+	ujianList, err := s.getUjian(ctx, url, "uts")
+	if err != nil {
+		return nil, err
+	}
+
+	var utsList []models.UTS
+	for _, ujian := range ujianList {
+		utsList = append(utsList, models.UTS{Ujian: ujian})
+	}
 	return utsList, nil
 }
 
+func (s *Scraper) GetUAS(ctx context.Context, url string) ([]models.UAS, error) {
+	ujianList, err := s.getUjian(ctx, url, "uas")
+	if err != nil {
+		return nil, err
+	}
+
+	var uasList []models.UAS
+	for _, ujian := range ujianList {
+		uasList = append(uasList, models.UAS{Ujian: ujian})
+	}
+	return uasList, nil
+}
+
+func (s *Scraper) GetUU(ctx context.Context, url string) ([]models.UU, error) {
+	ujianList, err := s.getUjian(ctx, url, "uu")
+	if err != nil {
+		return nil, err
+	}
+
+	var uuList []models.UU
+	for _, ujian := range ujianList {
+		uuList = append(uuList, models.UU{Ujian: ujian})
+	}
+	return uuList, nil
+}
+
 // EnsureSessionPublic is a public wrapper around ensureSession
-func EnsureSessionPublic() error {
-	return ensureSession()
+func EnsureSessionPublic(ctx context.Context) error {
+	return ensureSession(ctx)
 }